@@ -0,0 +1,205 @@
+package gintelemetry
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// BatchConfig tunes the batch processor that queues spans or log records
+// and exports them in the background. Zero values use the OTEL_BSP_* (traces)
+// or OTEL_BLRP_* (logs) environment variables, or the SDK defaults.
+type BatchConfig struct {
+	// Synchronous exports each span or log record as soon as it ends, on the
+	// calling goroutine, instead of batching. Nothing is lost when a
+	// short-lived job exits, but every call waits for the export.
+	Synchronous bool
+
+	// Blocking makes span recording wait for queue space when the queue is
+	// full, instead of dropping the span. Only applies to traces.
+	Blocking bool
+
+	// MaxQueueSize is the number of items buffered before new ones are dropped.
+	// Defaults to 2048.
+	MaxQueueSize int
+
+	// MaxExportBatchSize is the maximum number of items per export request.
+	// Must not exceed MaxQueueSize. Defaults to 512.
+	MaxExportBatchSize int
+
+	// ScheduleDelay is the maximum time between exports. Defaults to
+	// 5 seconds for traces and 1 second for logs.
+	ScheduleDelay time.Duration
+
+	// ExportTimeout is the maximum time one export may take. Defaults to 30 seconds.
+	ExportTimeout time.Duration
+}
+
+// validate fills unset fields from the OTEL_{prefix}_* variables, where
+// prefix is BSP or BLRP, falling back to the spec defaults.
+func (b *BatchConfig) validate(prefix string, defaultDelay time.Duration) error {
+	if b.MaxQueueSize == 0 {
+		n, err := envInt("OTEL_" + prefix + "_MAX_QUEUE_SIZE")
+		if err != nil {
+			return err
+		}
+		b.MaxQueueSize = n
+	}
+	if b.MaxQueueSize == 0 {
+		b.MaxQueueSize = 2048
+	}
+	if b.MaxExportBatchSize == 0 {
+		n, err := envInt("OTEL_" + prefix + "_MAX_EXPORT_BATCH_SIZE")
+		if err != nil {
+			return err
+		}
+		b.MaxExportBatchSize = n
+	}
+	if b.MaxExportBatchSize == 0 {
+		b.MaxExportBatchSize = min(512, b.MaxQueueSize)
+	}
+	if b.ScheduleDelay == 0 {
+		d, err := envMillis("OTEL_" + prefix + "_SCHEDULE_DELAY")
+		if err != nil {
+			return err
+		}
+		b.ScheduleDelay = d
+	}
+	if b.ScheduleDelay == 0 {
+		b.ScheduleDelay = defaultDelay
+	}
+	if b.ExportTimeout == 0 {
+		d, err := envMillis("OTEL_" + prefix + "_EXPORT_TIMEOUT")
+		if err != nil {
+			return err
+		}
+		b.ExportTimeout = d
+	}
+	if b.ExportTimeout == 0 {
+		b.ExportTimeout = 30 * time.Second
+	}
+
+	if b.MaxQueueSize < 0 || b.MaxExportBatchSize < 0 || b.ScheduleDelay < 0 || b.ExportTimeout < 0 {
+		return fmt.Errorf("gintelemetry: batch settings cannot be negative")
+	}
+	if b.MaxExportBatchSize > b.MaxQueueSize {
+		return fmt.Errorf("gintelemetry: MaxExportBatchSize (%d) cannot exceed MaxQueueSize (%d)",
+			b.MaxExportBatchSize, b.MaxQueueSize)
+	}
+	return nil
+}
+
+// spanProcessor returns the batch or synchronous processor for exporter.
+func (b BatchConfig) spanProcessor(exporter sdktrace.SpanExporter) sdktrace.SpanProcessor {
+	if b.Synchronous {
+		return sdktrace.NewSimpleSpanProcessor(exporter)
+	}
+	opts := []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithMaxQueueSize(b.MaxQueueSize),
+		sdktrace.WithMaxExportBatchSize(b.MaxExportBatchSize),
+		sdktrace.WithBatchTimeout(b.ScheduleDelay),
+		sdktrace.WithExportTimeout(b.ExportTimeout),
+	}
+	if b.Blocking {
+		opts = append(opts, sdktrace.WithBlocking())
+	}
+	return sdktrace.NewBatchSpanProcessor(exporter, opts...)
+}
+
+// logProcessor returns the batch or synchronous processor for exporter.
+func (b BatchConfig) logProcessor(exporter sdklog.Exporter) sdklog.Processor {
+	if b.Synchronous {
+		return sdklog.NewSimpleProcessor(exporter)
+	}
+	return sdklog.NewBatchProcessor(exporter,
+		sdklog.WithMaxQueueSize(b.MaxQueueSize),
+		sdklog.WithExportMaxBatchSize(b.MaxExportBatchSize),
+		sdklog.WithExportInterval(b.ScheduleDelay),
+		sdklog.WithExportTimeout(b.ExportTimeout),
+	)
+}
+
+// LimitsConfig bounds the size of spans and log records. Zero values use the
+// OTEL_*_LIMIT environment variables, or the SDK defaults.
+type LimitsConfig struct {
+	// AttributeCount is the maximum number of attributes on a span, span
+	// event, span link or log record. Extra attributes are dropped.
+	// Defaults to OTEL_ATTRIBUTE_COUNT_LIMIT, or 128.
+	AttributeCount int
+
+	// AttributeValueLength truncates longer string attribute values. Negative
+	// means unlimited. Defaults to OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT, or unlimited.
+	AttributeValueLength int
+
+	// SpanEvents is the maximum number of events per span.
+	// Defaults to OTEL_SPAN_EVENT_COUNT_LIMIT, or 128.
+	SpanEvents int
+
+	// SpanLinks is the maximum number of links per span.
+	// Defaults to OTEL_SPAN_LINK_COUNT_LIMIT, or 128.
+	SpanLinks int
+}
+
+func (l *LimitsConfig) validate() error {
+	fields := []struct {
+		value *int
+		env   string
+		def   int
+	}{
+		{&l.AttributeCount, "OTEL_ATTRIBUTE_COUNT_LIMIT", 128},
+		{&l.AttributeValueLength, "OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT", -1},
+		{&l.SpanEvents, "OTEL_SPAN_EVENT_COUNT_LIMIT", 128},
+		{&l.SpanLinks, "OTEL_SPAN_LINK_COUNT_LIMIT", 128},
+	}
+	for _, f := range fields {
+		if *f.value == 0 {
+			n, err := envInt(f.env)
+			if err != nil {
+				return err
+			}
+			*f.value = n
+		}
+		if *f.value == 0 {
+			*f.value = f.def
+		}
+	}
+	if l.AttributeValueLength < 0 {
+		l.AttributeValueLength = -1
+	}
+	return nil
+}
+
+func (l LimitsConfig) spanLimits() sdktrace.SpanLimits {
+	return sdktrace.SpanLimits{
+		AttributeValueLengthLimit:   l.AttributeValueLength,
+		AttributeCountLimit:         l.AttributeCount,
+		EventCountLimit:             l.SpanEvents,
+		LinkCountLimit:              l.SpanLinks,
+		AttributePerEventCountLimit: l.AttributeCount,
+		AttributePerLinkCountLimit:  l.AttributeCount,
+	}
+}
+
+// envInt reads a non-negative integer variable. It returns 0 when key is unset.
+func envInt(key string) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("gintelemetry: invalid %s %q", key, v)
+	}
+	return n, nil
+}
+
+// envBool reports whether key is set to "true", ignoring case. The spec
+// treats every other value as false.
+func envBool(key string) bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(key)), "true")
+}
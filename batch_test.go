@@ -0,0 +1,221 @@
+package gintelemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// batchRecorder is a span and log exporter that records the size and the
+// time left before the deadline of every export.
+type batchRecorder struct {
+	mu       sync.Mutex
+	batches  []int
+	timeouts []time.Duration
+}
+
+func (r *batchRecorder) record(ctx context.Context, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, n)
+	if deadline, ok := ctx.Deadline(); ok {
+		r.timeouts = append(r.timeouts, time.Until(deadline))
+	}
+}
+
+func (r *batchRecorder) exported() (batches []int, timeouts []time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.batches...), append([]time.Duration(nil), r.timeouts...)
+}
+
+func (r *batchRecorder) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	r.record(ctx, len(spans))
+	return nil
+}
+
+func (r *batchRecorder) Export(ctx context.Context, records []sdklog.Record) error {
+	r.record(ctx, len(records))
+	return nil
+}
+
+func (r *batchRecorder) ForceFlush(context.Context) error { return nil }
+func (r *batchRecorder) Shutdown(context.Context) error   { return nil }
+
+// waitExported waits until r has exported n items in total.
+func waitExported(t *testing.T, r *batchRecorder, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		batches, _ := r.exported()
+		total := 0
+		for _, b := range batches {
+			total += b
+		}
+		if total >= n {
+			return
+		}
+	}
+	t.Fatalf("exported fewer than %d items within 2s", n)
+}
+
+func validBatch(t *testing.T, b BatchConfig, prefix string) BatchConfig {
+	t.Helper()
+	if err := b.validate(prefix, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func checkBatches(t *testing.T, r *batchRecorder, maxBatch int, timeout time.Duration) {
+	t.Helper()
+	batches, timeouts := r.exported()
+	total := 0
+	for _, b := range batches {
+		total += b
+		if b > maxBatch {
+			t.Errorf("exported a batch of %d, want at most %d", b, maxBatch)
+		}
+	}
+	if total != 5 {
+		t.Errorf("exported %d items in %v, want 5", total, batches)
+	}
+	if len(timeouts) == 0 {
+		t.Fatal("exports had no deadline")
+	}
+	for _, d := range timeouts {
+		if d <= 0 || d > timeout {
+			t.Errorf("export deadline in %v, want within %v", d, timeout)
+		}
+	}
+}
+
+func TestBatchConfig_SpanProcessor(t *testing.T) {
+	r := &batchRecorder{}
+	b := validBatch(t, BatchConfig{MaxQueueSize: 10, MaxExportBatchSize: 2, ExportTimeout: 3 * time.Second}, "BSP")
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(b.spanProcessor(r)))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	for range 5 {
+		_, span := tp.Tracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	checkBatches(t, r, 2, 3*time.Second)
+}
+
+func TestBatchConfig_LogProcessor(t *testing.T) {
+	r := &batchRecorder{}
+	b := validBatch(t, BatchConfig{MaxQueueSize: 10, MaxExportBatchSize: 2, ExportTimeout: 3 * time.Second}, "BLRP")
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(b.logProcessor(r)))
+	t.Cleanup(func() { _ = lp.Shutdown(context.Background()) })
+
+	for range 5 {
+		lp.Logger("test").Emit(context.Background(), log.Record{})
+	}
+	if err := lp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	checkBatches(t, r, 2, 3*time.Second)
+}
+
+// A short ScheduleDelay exports without a flush; the default would not
+// within the test.
+func TestBatchConfig_ScheduleDelay(t *testing.T) {
+	b := validBatch(t, BatchConfig{ScheduleDelay: 10 * time.Millisecond}, "BSP")
+
+	spans := &batchRecorder{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(b.spanProcessor(spans)))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	waitExported(t, spans, 1)
+
+	logs := &batchRecorder{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(b.logProcessor(logs)))
+	t.Cleanup(func() { _ = lp.Shutdown(context.Background()) })
+	lp.Logger("test").Emit(context.Background(), log.Record{})
+	waitExported(t, logs, 1)
+}
+
+func TestBatchConfig_Synchronous(t *testing.T) {
+	b := validBatch(t, BatchConfig{Synchronous: true}, "BSP")
+
+	spans := &batchRecorder{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(b.spanProcessor(spans)))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	if got, _ := spans.exported(); len(got) != 1 {
+		t.Errorf("span exports after End = %v, want 1", got)
+	}
+
+	logs := &batchRecorder{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(b.logProcessor(logs)))
+	t.Cleanup(func() { _ = lp.Shutdown(context.Background()) })
+	lp.Logger("test").Emit(context.Background(), log.Record{})
+	if got, _ := logs.exported(); len(got) != 1 {
+		t.Errorf("log exports after Emit = %v, want 1", got)
+	}
+}
+
+// Synchronous spans and log records reach the exporters configured through
+// Start as they end, without a flush or shutdown.
+func TestStart_SynchronousBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	cfg := NewTestConfig("test")
+	cfg.Exporter = ExporterNone
+	cfg.Traces = SignalConfig{Exporter: ExporterFile, File: filepath.Join(dir, "traces.jsonl")}
+	cfg.Logs = SignalConfig{Exporter: ExporterFile, File: filepath.Join(dir, "logs.jsonl")}
+	cfg.TraceBatch = BatchConfig{Synchronous: true}
+	cfg.LogBatch = BatchConfig{Synchronous: true}
+	tel, _, err := Start(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = tel.Shutdown(context.Background()) })
+
+	_, end := tel.Trace().StartSpan(context.Background(), "job")
+	end()
+	tel.Log().Error(context.Background(), "job failed")
+
+	for file, want := range map[string]string{"traces.jsonl": `"Name":"job"`, "logs.jsonl": "job failed"} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s = %q, want %s", file, data, want)
+		}
+	}
+}
+
+func TestBatchConfig_Validate(t *testing.T) {
+	t.Setenv("OTEL_BSP_MAX_QUEUE_SIZE", "100")
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "250")
+
+	b := BatchConfig{MaxQueueSize: 50}
+	if err := b.validate("BSP", 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	want := BatchConfig{MaxQueueSize: 50, MaxExportBatchSize: 50, ScheduleDelay: 250 * time.Millisecond, ExportTimeout: 30 * time.Second}
+	if b != want {
+		t.Errorf("validate() = %+v, want %+v", b, want)
+	}
+
+	b = BatchConfig{MaxQueueSize: 10, MaxExportBatchSize: 20}
+	if err := b.validate("BSP", 5*time.Second); err == nil {
+		t.Error("validate() accepted MaxExportBatchSize above MaxQueueSize")
+	}
+}
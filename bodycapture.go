@@ -0,0 +1,336 @@
+package gintelemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"mime"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BodyOutput selects where captured bodies are recorded.
+type BodyOutput string
+
+const (
+	// BodyOutputSpanEvent adds an "http.body" event to the request span (default).
+	BodyOutputSpanEvent BodyOutput = "span_event"
+
+	// BodyOutputLog writes an Info log record correlated with the request span.
+	BodyOutputLog BodyOutput = "log"
+)
+
+// BodyCaptureConfig configures request and response body capture for
+// debugging. Nothing is captured until routes are enabled or Ratio is set.
+type BodyCaptureConfig struct {
+	// Routes lists Gin route templates, e.g. "/orders/:id", to capture from
+	// the start. Use BodyCapture.Enable and Disable to change them at runtime.
+	Routes []string
+
+	// Ratio is the fraction of requests on any route to capture, between 0
+	// and 1. Use BodyCapture.SetRatio to change it at runtime.
+	Ratio float64
+
+	// ContentTypes lists the media types to capture, with path.Match
+	// wildcards such as "text/*". Other bodies are skipped. Defaults to
+	// application/json, application/*+json and text/plain.
+	ContentTypes []string
+
+	// MaxSize truncates bodies longer than this many bytes. Defaults to 4096.
+	MaxSize int
+
+	// RedactFields lists JSON field names, matched case-insensitively at any
+	// depth, whose values are replaced with "[REDACTED]".
+	RedactFields []string
+
+	// Output selects span events or log records. Defaults to BodyOutputSpanEvent.
+	Output BodyOutput
+}
+
+func (b *BodyCaptureConfig) validate() error {
+	if b.Ratio < 0 || b.Ratio > 1 {
+		return fmt.Errorf("gintelemetry: body capture Ratio must be between 0 and 1, got %v", b.Ratio)
+	}
+	if b.ContentTypes == nil {
+		b.ContentTypes = []string{"application/json", "application/*+json", "text/plain"}
+	}
+	for _, ct := range b.ContentTypes {
+		if _, err := path.Match(ct, ""); err != nil {
+			return fmt.Errorf("gintelemetry: invalid body capture content type %q: %w", ct, err)
+		}
+	}
+	if b.MaxSize < 0 {
+		return fmt.Errorf("gintelemetry: body capture MaxSize cannot be negative")
+	}
+	if b.MaxSize == 0 {
+		b.MaxSize = 4096
+	}
+	switch b.Output {
+	case "":
+		b.Output = BodyOutputSpanEvent
+	case BodyOutputSpanEvent, BodyOutputLog:
+	default:
+		return fmt.Errorf("gintelemetry: unsupported body capture output %q", b.Output)
+	}
+	return nil
+}
+
+// BodyCapture records request and response bodies of selected requests.
+// Create one with Telemetry.BodyCapture and add its Middleware after the
+// telemetry middleware. It is safe for concurrent use, so routes can be
+// enabled from an admin endpoint while serving traffic.
+type BodyCapture struct {
+	cfg    BodyCaptureConfig
+	logger *slog.Logger
+	redact map[string]bool
+
+	mu     sync.RWMutex
+	routes map[string]bool
+	ratio  atomic.Uint64 // math.Float64bits of the ratio
+}
+
+// BodyCapture creates a body capture middleware. It returns an error when
+// cfg is invalid.
+//
+// Example:
+//
+//	capture, err := tel.BodyCapture(gintelemetry.BodyCaptureConfig{
+//	    RedactFields: []string{"password", "card_number"},
+//	})
+//	router.Use(capture.Middleware())
+//	admin.POST("/debug/capture", func(c *gin.Context) {
+//	    capture.Enable(c.Query("route"))
+//	})
+func (t *Telemetry) BodyCapture(cfg BodyCaptureConfig) (*BodyCapture, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	b := &BodyCapture{
+		cfg:    cfg,
+		redact: make(map[string]bool, len(cfg.RedactFields)),
+		routes: make(map[string]bool, len(cfg.Routes)),
+	}
+	if t != nil {
+		b.logger = t.logger
+	}
+	for _, f := range cfg.RedactFields {
+		b.redact[strings.ToLower(f)] = true
+	}
+	b.Enable(cfg.Routes...)
+	b.SetRatio(cfg.Ratio)
+	return b, nil
+}
+
+// Enable starts capturing bodies for the given route templates.
+func (b *BodyCapture) Enable(routes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range routes {
+		b.routes[r] = true
+	}
+}
+
+// Disable stops capturing bodies for the given route templates.
+func (b *BodyCapture) Disable(routes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range routes {
+		delete(b.routes, r)
+	}
+}
+
+// Routes returns the enabled route templates, sorted.
+func (b *BodyCapture) Routes() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	routes := make([]string, 0, len(b.routes))
+	for r := range b.routes {
+		routes = append(routes, r)
+	}
+	slices.Sort(routes)
+	return routes
+}
+
+// SetRatio sets the fraction of requests on any route to capture. Values
+// are clamped to [0, 1].
+func (b *BodyCapture) SetRatio(ratio float64) {
+	b.ratio.Store(math.Float64bits(min(max(ratio, 0), 1)))
+}
+
+func (b *BodyCapture) selected(c *gin.Context) bool {
+	b.mu.RLock()
+	enabled := b.routes[c.FullPath()]
+	b.mu.RUnlock()
+	if enabled {
+		return true
+	}
+	ratio := math.Float64frombits(b.ratio.Load())
+	return ratio > 0 && rand.Float64() < ratio
+}
+
+// Middleware returns the Gin middleware that captures bodies. Register it
+// after Telemetry.Middleware so that the request span exists.
+func (b *BodyCapture) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if b == nil || !b.selected(c) {
+			c.Next()
+			return
+		}
+
+		var attrs []attribute.KeyValue
+		if c.Request.Body != nil && b.capturable(c.ContentType()) {
+			// Read up to one byte past the limit to detect truncation, then
+			// put the bytes back for the handler.
+			head, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(b.cfg.MaxSize)+1))
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+			attrs = append(attrs, b.bodyAttributes("http.request.body", c.ContentType(), head)...)
+		}
+
+		w := &bodyWriter{ResponseWriter: c.Writer, limit: b.cfg.MaxSize + 1}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if contentType := w.Header().Get("Content-Type"); b.capturable(contentType) {
+			attrs = append(attrs, b.bodyAttributes("http.response.body", contentType, w.body.Bytes())...)
+		}
+		if len(attrs) == 0 {
+			return
+		}
+
+		ctx := c.Request.Context()
+		if b.cfg.Output == BodyOutputLog {
+			if b.logger != nil {
+				args := make([]any, len(attrs))
+				for i, kv := range attrs {
+					args[i] = slog.Any(string(kv.Key), kv.Value.AsInterface())
+				}
+				b.logger.InfoContext(ctx, "http body captured", args...)
+			}
+			return
+		}
+		trace.SpanFromContext(ctx).AddEvent("http.body", trace.WithAttributes(attrs...))
+	}
+}
+
+func (b *BodyCapture) capturable(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range b.cfg.ContentTypes {
+		if ok, _ := path.Match(pattern, mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyAttributes returns the attributes for a captured body, where body
+// may be one byte longer than MaxSize to signal truncation.
+func (b *BodyCapture) bodyAttributes(key, contentType string, body []byte) []attribute.KeyValue {
+	if len(body) == 0 {
+		return nil
+	}
+	truncated := len(body) > b.cfg.MaxSize
+	if truncated {
+		body = body[:b.cfg.MaxSize]
+	}
+	value := string(body)
+	if len(b.redact) > 0 && strings.Contains(contentType, "json") {
+		value = redactJSON(body, truncated, b.redact)
+	}
+	return []attribute.KeyValue{
+		attribute.String(key, value),
+		attribute.Bool(key+".truncated", truncated),
+	}
+}
+
+// jsonKey matches a JSON object key, capturing the key.
+var jsonKey = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:`)
+
+// redactJSON replaces the values of fields in body. A complete body is
+// parsed and re-encoded. A truncated body cannot be parsed, so it is cut
+// before the first redacted field instead.
+func redactJSON(body []byte, truncated bool, fields map[string]bool) string {
+	if !truncated {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err == nil {
+			if out, err := json.Marshal(redactValue(v, fields)); err == nil {
+				return string(out)
+			}
+		}
+	}
+	for _, m := range jsonKey.FindAllSubmatchIndex(body, -1) {
+		// Unquote the key so that escapes such as "p\u0061ssword" match.
+		var key string
+		if err := json.Unmarshal(body[m[0]:m[3]+1], &key); err != nil {
+			key = string(body[m[2]:m[3]])
+		}
+		if fields[strings.ToLower(key)] {
+			return string(body[:m[1]]) + ` "[REDACTED]"`
+		}
+	}
+	return string(body)
+}
+
+func redactValue(v any, fields map[string]bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if fields[strings.ToLower(k)] {
+				v[k] = "[REDACTED]"
+			} else {
+				v[k] = redactValue(child, fields)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactValue(child, fields)
+		}
+	}
+	return v
+}
+
+// readCloser reads from the replayed body but closes the original.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter keeps the first limit bytes of the response body.
+type bodyWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *bodyWriter) Write(p []byte) (int, error) {
+	w.keep(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) keep(p []byte) {
+	if room := w.limit - w.body.Len(); room > 0 {
+		w.body.Write(p[:min(len(p), room)])
+	}
+}
@@ -0,0 +1,284 @@
+package gintelemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newBodyCaptureTest returns a router with the telemetry and body capture
+// middleware, and /orders and /users routes that echo the request body.
+func newBodyCaptureTest(t *testing.T, cfg BodyCaptureConfig) (*gin.Engine, *BodyCapture, *tracetest.SpanRecorder) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	tcfg := NewTestConfig("test")
+	tcfg.Exporter = ExporterNone
+	tcfg.SpanProcessors = []sdktrace.SpanProcessor{recorder}
+	tel, router, err := Start(context.Background(), tcfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = tel.Shutdown(context.Background()) })
+
+	capture, err := tel.BodyCapture(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.Use(capture.Middleware())
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, c.GetHeader("Content-Type"), body)
+	}
+	router.POST("/orders", echo)
+	router.POST("/users", echo)
+	return router, capture, recorder
+}
+
+// post sends body to path and returns the echoed response body.
+func post(router *gin.Engine, path, contentType, body string) string {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+// bodyEvent returns the attributes of the http.body event of the last
+// ended span, or nil when it has none.
+func bodyEvent(t *testing.T, recorder *tracetest.SpanRecorder) map[attribute.Key]attribute.Value {
+	t.Helper()
+	spans := recorder.Ended()
+	if len(spans) == 0 {
+		t.Fatal("no span ended")
+	}
+	for _, e := range spans[len(spans)-1].Events() {
+		if e.Name != "http.body" {
+			continue
+		}
+		attrs := make(map[attribute.Key]attribute.Value, len(e.Attributes))
+		for _, kv := range e.Attributes {
+			attrs[kv.Key] = kv.Value
+		}
+		return attrs
+	}
+	return nil
+}
+
+// spanContains reports whether any attribute of the span or its events
+// contains s.
+func spanContains(span sdktrace.ReadOnlySpan, s string) bool {
+	attrs := span.Attributes()
+	for _, e := range span.Events() {
+		attrs = append(attrs, e.Attributes...)
+	}
+	for _, kv := range attrs {
+		if strings.Contains(kv.Value.Emit(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBodyCapture_RedactsJSONFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxSize       int
+		contentType   string
+		body          string
+		want          string
+		wantTruncated bool
+	}{
+		{
+			name:        "complete body",
+			contentType: "application/json",
+			body:        `{"user":"ann","password":"hunter2"}`,
+			want:        `{"password":"[REDACTED]","user":"ann"}`,
+		},
+		{
+			name:        "nested field in an array, any case",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"items":[{"Card_Number":"hunter2"}],"note":"ok"}`,
+			want:        `{"items":[{"Card_Number":"[REDACTED]"}],"note":"ok"}`,
+		},
+		{
+			name:        "structured syntax suffix",
+			contentType: "application/problem+json",
+			body:        `{"detail":{"password":{"old":"hunter2"}}}`,
+			want:        `{"detail":{"password":"[REDACTED]"}}`,
+		},
+		{
+			name:          "truncated inside the redacted value",
+			maxSize:       30,
+			contentType:   "application/json",
+			body:          `{"user":"ann","password":"hunter2-hunter2"}`,
+			want:          `{"user":"ann","password": "[REDACTED]"`,
+			wantTruncated: true,
+		},
+		{
+			name:          "truncated after the redacted value",
+			maxSize:       45,
+			contentType:   "application/json",
+			body:          `{"password":"hunter2","user":"ann","note":"0123456789"}`,
+			want:          `{"password": "[REDACTED]"`,
+			wantTruncated: true,
+		},
+		{
+			name:          "truncated with an escaped key",
+			maxSize:       40,
+			contentType:   "application/json",
+			body:          `{"user":"ann","p\u0061ssword":"hunter2-hunter2-hunter2"}`,
+			want:          `{"user":"ann","p\u0061ssword": "[REDACTED]"`,
+			wantTruncated: true,
+		},
+		{
+			name:          "truncated before any redacted key",
+			maxSize:       10,
+			contentType:   "application/json",
+			body:          `{"user":"ann","password":"hunter2"}`,
+			want:          `{"user":"a`,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _, recorder := newBodyCaptureTest(t, BodyCaptureConfig{
+				Routes:       []string{"/orders"},
+				MaxSize:      tt.maxSize,
+				RedactFields: []string{"password", "card_number"},
+			})
+
+			if got := post(router, "/orders", tt.contentType, tt.body); got != tt.body {
+				t.Errorf("handler echoed %q, want the full body %q", got, tt.body)
+			}
+
+			attrs := bodyEvent(t, recorder)
+			for _, key := range []attribute.Key{"http.request.body", "http.response.body"} {
+				if got := attrs[key].AsString(); got != tt.want {
+					t.Errorf("%s = %q, want %q", key, got, tt.want)
+				}
+				if got := attrs[key+".truncated"].AsBool(); got != tt.wantTruncated {
+					t.Errorf("%s.truncated = %v, want %v", key, got, tt.wantTruncated)
+				}
+			}
+			for _, span := range recorder.Ended() {
+				if spanContains(span, "hunter2") {
+					t.Errorf("span %s contains a redacted value", span.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestBodyCapture_ContentTypesAndSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentType   string
+		body          string
+		want          string
+		wantTruncated bool
+		wantSkipped   bool
+	}{
+		{name: "text", contentType: "text/plain", body: "hello", want: "hello"},
+		{name: "text is not redacted", contentType: "text/plain", body: "password", want: "password"},
+		{name: "truncated", contentType: "text/plain", body: "hello world", want: "hello wo", wantTruncated: true},
+		{name: "html", contentType: "text/html", body: "<p>hi</p>", wantSkipped: true},
+		{name: "invalid content type", contentType: "text/", body: "hello", wantSkipped: true},
+		{name: "empty body", contentType: "application/json", wantSkipped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _, recorder := newBodyCaptureTest(t, BodyCaptureConfig{
+				Routes:       []string{"/orders"},
+				MaxSize:      8,
+				RedactFields: []string{"password"},
+			})
+			if got := post(router, "/orders", tt.contentType, tt.body); got != tt.body {
+				t.Errorf("handler echoed %q, want the full body %q", got, tt.body)
+			}
+
+			attrs := bodyEvent(t, recorder)
+			if tt.wantSkipped {
+				if attrs != nil {
+					t.Errorf("captured %v, want nothing", attrs)
+				}
+				return
+			}
+			if got := attrs["http.request.body"].AsString(); got != tt.want {
+				t.Errorf("http.request.body = %q, want %q", got, tt.want)
+			}
+			if got := attrs["http.request.body.truncated"].AsBool(); got != tt.wantTruncated {
+				t.Errorf("http.request.body.truncated = %v, want %v", got, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestBodyCapture_Runtime(t *testing.T) {
+	router, capture, recorder := newBodyCaptureTest(t, BodyCaptureConfig{})
+
+	steps := []struct {
+		name   string
+		change func()
+		path   string
+		want   bool
+	}{
+		{name: "nothing enabled", change: func() {}, path: "/orders"},
+		{name: "enabled route", change: func() { capture.Enable("/orders") }, path: "/orders", want: true},
+		{name: "other route", change: func() {}, path: "/users"},
+		{name: "disabled route", change: func() { capture.Disable("/orders") }, path: "/orders"},
+		{name: "ratio 1", change: func() { capture.SetRatio(1) }, path: "/users", want: true},
+		{name: "ratio 0", change: func() { capture.SetRatio(0) }, path: "/users"},
+		{name: "ratio clamped to 1", change: func() { capture.SetRatio(5) }, path: "/users", want: true},
+		{name: "ratio clamped to 0", change: func() { capture.SetRatio(-1) }, path: "/users"},
+	}
+	for _, step := range steps {
+		step.change()
+		post(router, step.path, "text/plain", "hello")
+		if got := bodyEvent(t, recorder) != nil; got != step.want {
+			t.Errorf("%s: captured = %v, want %v", step.name, got, step.want)
+		}
+	}
+
+	capture.Enable("/users", "/orders")
+	if got := capture.Routes(); len(got) != 2 || got[0] != "/orders" || got[1] != "/users" {
+		t.Errorf("Routes() = %v, want [/orders /users]", got)
+	}
+}
+
+func TestBodyCaptureConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     BodyCaptureConfig
+		wantErr bool
+	}{
+		{name: "defaults", cfg: BodyCaptureConfig{}},
+		{name: "ratio above 1", cfg: BodyCaptureConfig{Ratio: 1.5}, wantErr: true},
+		{name: "negative ratio", cfg: BodyCaptureConfig{Ratio: -0.1}, wantErr: true},
+		{name: "negative size", cfg: BodyCaptureConfig{MaxSize: -1}, wantErr: true},
+		{name: "bad content type pattern", cfg: BodyCaptureConfig{ContentTypes: []string{"text/["}}, wantErr: true},
+		{name: "unknown output", cfg: BodyCaptureConfig{Output: "stdout"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (tt.cfg.MaxSize != 4096 || tt.cfg.Output != BodyOutputSpanEvent || len(tt.cfg.ContentTypes) == 0) {
+				t.Errorf("validate() defaults = %+v", tt.cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package gintelemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// CardinalityConfig bounds the number of distinct attribute sets (series)
+// each metric instrument may record. Once an instrument reaches its limit,
+// measurements with new attribute sets are recorded under a single
+// otel.metric.overflow=true series instead, a warning is logged, and the
+// gintelemetry.metric.overflow counter is incremented.
+//
+// Example:
+//
+//	Cardinality: gintelemetry.CardinalityConfig{
+//	    Limit:       1000,
+//	    Instruments: map[string]int{"http.server.request.duration": 5000},
+//	}
+type CardinalityConfig struct {
+	// Limit is the maximum number of series per instrument, including the
+	// overflow series. Negative means unlimited. Defaults to 2000.
+	Limit int
+
+	// Instruments overrides Limit for instruments with these names.
+	// Negative means unlimited.
+	Instruments map[string]int
+}
+
+func (c *CardinalityConfig) validate() error {
+	if c.Limit == 0 {
+		c.Limit = 2000
+	}
+	for name, limit := range c.Instruments {
+		if limit == 0 {
+			return fmt.Errorf("gintelemetry: cardinality limit for %q cannot be zero", name)
+		}
+	}
+	return nil
+}
+
+// sdkLimit returns the limit for the SDK meter provider, which applies to
+// every instrument and so must be the largest configured limit, or 0 for
+// none.
+func (c CardinalityConfig) sdkLimit() int {
+	limit := c.Limit
+	for _, l := range c.Instruments {
+		if l < 0 || limit < 0 {
+			return 0
+		}
+		limit = max(limit, l)
+	}
+	return max(limit, 0)
+}
+
+// overflowKey marks the series that replaces new attribute sets once an
+// instrument is over its limit, matching the SDK's own overflow series.
+const overflowKey = attribute.Key("otel.metric.overflow")
+
+var overflowSet = attribute.NewSet(overflowKey.Bool(true))
+
+// cardinalityLimits enforces CardinalityConfig for the instruments created
+// through a limitedMeterProvider, after redacting their attributes.
+type cardinalityLimits struct {
+	cfg       CardinalityConfig
+	view      sdkmetric.View // nil without views
+	overflows metric.Int64Counter
+	logger    *slog.Logger
+	redactor  *redactor
+
+	mu       sync.Mutex
+	limiters map[string]*seriesLimiter
+}
+
+// newCardinalityLimits returns the limits for cfg. Attribute sets are counted
+// after views drop attributes, so that the limit applies to the exported
+// series. The views must have been validated.
+func newCardinalityLimits(cfg CardinalityConfig, r *redactor, views []View) *cardinalityLimits {
+	c := &cardinalityLimits{
+		cfg:      cfg,
+		redactor: r,
+		limiters: make(map[string]*seriesLimiter),
+	}
+	if len(views) > 0 {
+		c.view = newView(views)
+	}
+	return c
+}
+
+// registerMetrics creates the overflow counter on meter.
+func (c *cardinalityLimits) registerMetrics(meter metric.Meter) error {
+	overflows, err := meter.Int64Counter("gintelemetry.metric.overflow",
+		metric.WithDescription("Measurements recorded into the overflow series because an instrument reached its cardinality limit"))
+	if err != nil {
+		return err
+	}
+	c.overflows = overflows
+	return nil
+}
+
+// limiter returns the shared limiter for an instrument, or nil when the
+// instrument is unlimited and nothing is redacted.
+func (c *cardinalityLimits) limiter(scope, name string, kind sdkmetric.InstrumentKind) *seriesLimiter {
+	limit := c.cfg.Limit
+	if l, ok := c.cfg.Instruments[name]; ok {
+		limit = l
+	}
+	if limit < 0 && c.redactor == nil {
+		return nil
+	}
+
+	// Count series as the view exports them.
+	var filter attribute.Filter
+	if c.view != nil {
+		stream, ok := c.view(sdkmetric.Instrument{Name: name, Kind: kind, Scope: instrumentation.Scope{Name: scope}})
+		if ok {
+			if _, drop := stream.Aggregation.(sdkmetric.AggregationDrop); drop && c.redactor == nil {
+				return nil
+			}
+			filter = stream.AttributeFilter
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := scope + "\x00" + name
+	l, ok := c.limiters[key]
+	if !ok {
+		l = &seriesLimiter{
+			limits: c,
+			name:   name,
+			kind:   kind,
+			limit:  limit,
+			filter: filter,
+			sets:   make(map[attribute.Distinct]attribute.Set),
+		}
+		c.limiters[key] = l
+	}
+	return l
+}
+
+// reset forgets the attribute sets of instruments that temporality exports
+// as deltas, so that their limits apply per collection cycle like the SDK's.
+func (c *cardinalityLimits) reset(temporality sdkmetric.TemporalitySelector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, l := range c.limiters {
+		if temporality(l.kind) != metricdata.DeltaTemporality {
+			continue
+		}
+		l.mu.Lock()
+		clear(l.sets)
+		l.mu.Unlock()
+	}
+}
+
+// seriesLimiter tracks the attribute sets recorded by one instrument.
+type seriesLimiter struct {
+	limits *cardinalityLimits
+	name   string
+	kind   sdkmetric.InstrumentKind
+	limit  int              // negative for unlimited
+	filter attribute.Filter // the view's attribute filter, if any
+
+	mu   sync.Mutex
+	sets map[attribute.Distinct]attribute.Set
+	key  string // attribute key blamed for the overflow, set on first overflow
+}
+
+// admit reports whether set may be recorded as its own series. Sets seen
+// before always are; new sets are while the instrument is under its limit,
+// keeping one slot for the overflow series.
+func (l *seriesLimiter) admit(ctx context.Context, set attribute.Set) bool {
+	if l.limit < 0 {
+		return true
+	}
+	if l.filter != nil {
+		set, _ = set.Filter(l.filter)
+	}
+	l.mu.Lock()
+	if _, ok := l.sets[set.Equivalent()]; ok || len(l.sets) < l.limit-1 {
+		l.sets[set.Equivalent()] = set
+		l.mu.Unlock()
+		return true
+	}
+	first := l.key == ""
+	if first {
+		l.key = l.highestCardinalityKey(set)
+	}
+	key := l.key
+	l.mu.Unlock()
+
+	l.limits.overflows.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("instrument", l.name),
+		attribute.String("attribute_key", key)))
+	if first && l.limits.logger != nil {
+		l.limits.logger.WarnContext(ctx, "gintelemetry: metric cardinality limit reached",
+			"instrument", l.name, "limit", l.limit, "attribute_key", key)
+	}
+	return false
+}
+
+// highestCardinalityKey returns the attribute key with the most distinct
+// values across the recorded sets and rejected, which is most likely the
+// one causing the overflow. l.mu must be held.
+func (l *seriesLimiter) highestCardinalityKey(rejected attribute.Set) string {
+	values := make(map[attribute.Key]map[attribute.Value]struct{})
+	count := func(set attribute.Set) {
+		for _, kv := range set.ToSlice() {
+			if values[kv.Key] == nil {
+				values[kv.Key] = make(map[attribute.Value]struct{})
+			}
+			values[kv.Key][kv.Value] = struct{}{}
+		}
+	}
+	for _, set := range l.sets {
+		count(set)
+	}
+	count(rejected)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	best := ""
+	for _, k := range keys {
+		if best == "" || len(values[attribute.Key(k)]) > len(values[attribute.Key(best)]) {
+			best = k
+		}
+	}
+	return best
+}
+
+// addOptions returns opts with the measurement's attributes redacted, or
+// options recording into the overflow series when the redacted attribute set
+// is not admitted.
+func (l *seriesLimiter) addOptions(ctx context.Context, opts []metric.AddOption) []metric.AddOption {
+	if l == nil {
+		return opts
+	}
+	set := l.limits.redactor.set(metric.NewAddConfig(opts).Attributes())
+	if !l.admit(ctx, set) {
+		return []metric.AddOption{metric.WithAttributeSet(overflowSet)}
+	}
+	if l.limits.redactor == nil {
+		return opts
+	}
+	return []metric.AddOption{metric.WithAttributeSet(set)}
+}
+
+// recordOptions is addOptions for histograms and gauges.
+func (l *seriesLimiter) recordOptions(ctx context.Context, opts []metric.RecordOption) []metric.RecordOption {
+	if l == nil {
+		return opts
+	}
+	set := l.limits.redactor.set(metric.NewRecordConfig(opts).Attributes())
+	if !l.admit(ctx, set) {
+		return []metric.RecordOption{metric.WithAttributeSet(overflowSet)}
+	}
+	if l.limits.redactor == nil {
+		return opts
+	}
+	return []metric.RecordOption{metric.WithAttributeSet(set)}
+}
+
+// resettingExporter resets the limits of delta instruments after each
+// export of a periodic reader.
+type resettingExporter struct {
+	sdkmetric.Exporter
+	limits *cardinalityLimits
+}
+
+func (e resettingExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.limits.reset(e.Exporter.Temporality)
+	return err
+}
+
+// limitedMeterProvider applies cardinality limits and redaction to the
+// synchronous instruments of its meters. Observable instruments are only
+// bounded by the SDK limit and are not redacted.
+type limitedMeterProvider struct {
+	metric.MeterProvider
+	limits *cardinalityLimits
+}
+
+func (p limitedMeterProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return limitedMeter{Meter: p.MeterProvider.Meter(name, opts...), scope: name, limits: p.limits}
+}
+
+type limitedMeter struct {
+	metric.Meter
+	scope  string
+	limits *cardinalityLimits
+}
+
+func (m limitedMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	inst, err := m.Meter.Int64Counter(name, opts...)
+	return limitedInt64Counter{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindCounter)}, err
+}
+
+func (m limitedMeter) Int64UpDownCounter(name string, opts ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	inst, err := m.Meter.Int64UpDownCounter(name, opts...)
+	return limitedInt64UpDownCounter{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindUpDownCounter)}, err
+}
+
+func (m limitedMeter) Int64Histogram(name string, opts ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	inst, err := m.Meter.Int64Histogram(name, opts...)
+	return limitedInt64Histogram{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindHistogram)}, err
+}
+
+func (m limitedMeter) Int64Gauge(name string, opts ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	inst, err := m.Meter.Int64Gauge(name, opts...)
+	return limitedInt64Gauge{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindGauge)}, err
+}
+
+func (m limitedMeter) Float64Counter(name string, opts ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	inst, err := m.Meter.Float64Counter(name, opts...)
+	return limitedFloat64Counter{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindCounter)}, err
+}
+
+func (m limitedMeter) Float64UpDownCounter(name string, opts ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	inst, err := m.Meter.Float64UpDownCounter(name, opts...)
+	return limitedFloat64UpDownCounter{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindUpDownCounter)}, err
+}
+
+func (m limitedMeter) Float64Histogram(name string, opts ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	inst, err := m.Meter.Float64Histogram(name, opts...)
+	return limitedFloat64Histogram{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindHistogram)}, err
+}
+
+func (m limitedMeter) Float64Gauge(name string, opts ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	inst, err := m.Meter.Float64Gauge(name, opts...)
+	return limitedFloat64Gauge{inst, m.limits.limiter(m.scope, name, sdkmetric.InstrumentKindGauge)}, err
+}
+
+type limitedInt64Counter struct {
+	metric.Int64Counter
+	limiter *seriesLimiter
+}
+
+func (c limitedInt64Counter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	c.Int64Counter.Add(ctx, incr, c.limiter.addOptions(ctx, opts)...)
+}
+
+type limitedInt64UpDownCounter struct {
+	metric.Int64UpDownCounter
+	limiter *seriesLimiter
+}
+
+func (c limitedInt64UpDownCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	c.Int64UpDownCounter.Add(ctx, incr, c.limiter.addOptions(ctx, opts)...)
+}
+
+type limitedInt64Histogram struct {
+	metric.Int64Histogram
+	limiter *seriesLimiter
+}
+
+func (h limitedInt64Histogram) Record(ctx context.Context, value int64, opts ...metric.RecordOption) {
+	h.Int64Histogram.Record(ctx, value, h.limiter.recordOptions(ctx, opts)...)
+}
+
+type limitedInt64Gauge struct {
+	metric.Int64Gauge
+	limiter *seriesLimiter
+}
+
+func (g limitedInt64Gauge) Record(ctx context.Context, value int64, opts ...metric.RecordOption) {
+	g.Int64Gauge.Record(ctx, value, g.limiter.recordOptions(ctx, opts)...)
+}
+
+type limitedFloat64Counter struct {
+	metric.Float64Counter
+	limiter *seriesLimiter
+}
+
+func (c limitedFloat64Counter) Add(ctx context.Context, incr float64, opts ...metric.AddOption) {
+	c.Float64Counter.Add(ctx, incr, c.limiter.addOptions(ctx, opts)...)
+}
+
+type limitedFloat64UpDownCounter struct {
+	metric.Float64UpDownCounter
+	limiter *seriesLimiter
+}
+
+func (c limitedFloat64UpDownCounter) Add(ctx context.Context, incr float64, opts ...metric.AddOption) {
+	c.Float64UpDownCounter.Add(ctx, incr, c.limiter.addOptions(ctx, opts)...)
+}
+
+type limitedFloat64Histogram struct {
+	metric.Float64Histogram
+	limiter *seriesLimiter
+}
+
+func (h limitedFloat64Histogram) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	h.Float64Histogram.Record(ctx, value, h.limiter.recordOptions(ctx, opts)...)
+}
+
+type limitedFloat64Gauge struct {
+	metric.Float64Gauge
+	limiter *seriesLimiter
+}
+
+func (g limitedFloat64Gauge) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	g.Float64Gauge.Record(ctx, value, g.limiter.recordOptions(ctx, opts)...)
+}
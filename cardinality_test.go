@@ -0,0 +1,264 @@
+package gintelemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newCardinalityTest returns a limited meter provider recording into reader.
+func newCardinalityTest(t *testing.T, cfg CardinalityConfig, views []View, reader sdkmetric.Reader) (metric.MeterProvider, *cardinalityLimits) {
+	t.Helper()
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	limits := newCardinalityLimits(cfg, nil, views)
+	opts := []sdkmetric.Option{
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithCardinalityLimit(cfg.sdkLimit()),
+	}
+	if len(views) > 0 {
+		opts = append(opts, sdkmetric.WithView(newView(views)))
+	}
+	provider := sdkmetric.NewMeterProvider(opts...)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	if err := limits.registerMetrics(provider.Meter(instrumentationName)); err != nil {
+		t.Fatal(err)
+	}
+	return limitedMeterProvider{MeterProvider: provider, limits: limits}, limits
+}
+
+// int64Sum returns the data points of the named sum in rm.
+func int64Sum(t *testing.T, rm metricdata.ResourceMetrics, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("%s data = %T, want Sum[int64]", name, m.Data)
+			}
+			return sum.DataPoints
+		}
+	}
+	return nil
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) metricdata.ResourceMetrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	return rm
+}
+
+func overflowed(points []metricdata.DataPoint[int64]) int64 {
+	var n int64
+	for _, p := range points {
+		if v, ok := p.Attributes.Value(overflowKey); ok && v.AsBool() {
+			n += p.Value
+		}
+	}
+	return n
+}
+
+func TestCardinality_CountsSeriesAfterViews(t *testing.T) {
+	tests := []struct {
+		name         string
+		views        []View
+		wantSeries   int
+		wantOverflow int64
+	}{
+		{
+			name:       "view drops the per-user attribute",
+			views:      []View{{Instrument: "orders", AttributeKeys: []string{"status"}}},
+			wantSeries: 2,
+		},
+		{
+			name:         "without a view every user is a series",
+			wantSeries:   10,
+			wantOverflow: 41,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			mp, _ := newCardinalityTest(t, CardinalityConfig{Limit: 10}, tt.views, reader)
+			orders, err := mp.Meter("test").Int64Counter("orders")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			for i := range 50 {
+				orders.Add(ctx, 1, metric.WithAttributes(
+					attribute.String("status", []string{"ok", "failed"}[i%2]),
+					attribute.String("user.id", fmt.Sprint(i))))
+			}
+
+			points := int64Sum(t, collect(t, reader), "orders")
+			if len(points) != tt.wantSeries {
+				t.Errorf("series = %d, want %d", len(points), tt.wantSeries)
+			}
+			if got := overflowed(points); got != tt.wantOverflow {
+				t.Errorf("overflowed measurements = %d, want %d", got, tt.wantOverflow)
+			}
+		})
+	}
+}
+
+// The overflow series must keep its marker when a view filters attributes.
+func TestCardinality_OverflowMarkerSurvivesView(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	views := []View{{Instrument: "orders", AttributeKeys: []string{"user.id"}}}
+	mp, _ := newCardinalityTest(t, CardinalityConfig{Limit: 3}, views, reader)
+	orders, err := mp.Meter("test").Int64Counter("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 5 {
+		orders.Add(context.Background(), 1, metric.WithAttributes(attribute.String("user.id", fmt.Sprint(i))))
+	}
+
+	points := int64Sum(t, collect(t, reader), "orders")
+	if got := overflowed(points); got != 3 {
+		t.Errorf("overflowed measurements = %d, want 3 in %v", got, points)
+	}
+}
+
+func TestCardinality_Limits(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          CardinalityConfig
+		wantOverflow int64
+	}{
+		{name: "default limit", cfg: CardinalityConfig{Limit: 5}, wantOverflow: 16},
+		{name: "unlimited", cfg: CardinalityConfig{Limit: -1}},
+		{
+			name:         "per-instrument override",
+			cfg:          CardinalityConfig{Limit: 5, Instruments: map[string]int{"orders": 11}},
+			wantOverflow: 10,
+		},
+		{
+			name: "per-instrument unlimited",
+			cfg:  CardinalityConfig{Limit: 5, Instruments: map[string]int{"orders": -1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			mp, _ := newCardinalityTest(t, tt.cfg, nil, reader)
+			orders, err := mp.Meter("test").Int64Counter("orders")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range 20 {
+				orders.Add(context.Background(), 1, metric.WithAttributes(attribute.Int("id", i)))
+			}
+
+			rm := collect(t, reader)
+			if got := overflowed(int64Sum(t, rm, "orders")); got != tt.wantOverflow {
+				t.Errorf("overflowed measurements = %d, want %d", got, tt.wantOverflow)
+			}
+			var reported int64
+			for _, p := range int64Sum(t, rm, "gintelemetry.metric.overflow") {
+				reported += p.Value
+			}
+			if reported != tt.wantOverflow {
+				t.Errorf("gintelemetry.metric.overflow = %d, want %d", reported, tt.wantOverflow)
+			}
+		})
+	}
+}
+
+// deltaExporter counts the series of the orders instrument in each export.
+type deltaExporter struct {
+	series []int
+}
+
+func (e *deltaExporter) Temporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.DeltaTemporality
+}
+
+func (e *deltaExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+func (e *deltaExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "orders" {
+				n := 0
+				for _, p := range sum.DataPoints {
+					if _, ok := p.Attributes.Value(overflowKey); !ok {
+						n++
+					}
+				}
+				e.series = append(e.series, n)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *deltaExporter) ForceFlush(context.Context) error { return nil }
+func (e *deltaExporter) Shutdown(context.Context) error   { return nil }
+
+// With delta temporality the limit applies per collection cycle.
+func TestCardinality_DeltaResetsEachCycle(t *testing.T) {
+	exporter := &deltaExporter{}
+	cfg := CardinalityConfig{Limit: 4}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	limits := newCardinalityLimits(cfg, nil, nil)
+	reader := sdkmetric.NewPeriodicReader(resettingExporter{Exporter: exporter, limits: limits})
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	if err := limits.registerMetrics(provider.Meter(instrumentationName)); err != nil {
+		t.Fatal(err)
+	}
+	mp := limitedMeterProvider{MeterProvider: provider, limits: limits}
+	orders, err := mp.Meter("test").Int64Counter("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for cycle := range 2 {
+		for i := range 3 {
+			orders.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", cycle*3+i)))
+		}
+		if err := provider.ForceFlush(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []int{3, 3}
+	if fmt.Sprint(exporter.series) != fmt.Sprint(want) {
+		t.Errorf("series per export = %v, want %v", exporter.series, want)
+	}
+}
+
+func TestSeriesLimiter_HighestCardinalityKey(t *testing.T) {
+	l := &seriesLimiter{sets: make(map[attribute.Distinct]attribute.Set)}
+	for i := range 3 {
+		set := attribute.NewSet(attribute.String("status", "ok"), attribute.Int("user.id", i))
+		l.sets[set.Equivalent()] = set
+	}
+	rejected := attribute.NewSet(attribute.String("status", "failed"), attribute.Int("user.id", 3))
+	if got := l.highestCardinalityKey(rejected); got != "user.id" {
+		t.Errorf("highestCardinalityKey() = %q, want %q", got, "user.id")
+	}
+}
@@ -1,9 +1,15 @@
 package gintelemetry
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // Protocol defines the transport protocol for OTLP exporters.
@@ -20,32 +26,191 @@ const (
 // Config holds the configuration for initializing the telemetry stack.
 type Config struct {
 	// ServiceName is required and reported in all telemetry data.
+	// Defaults to OTEL_SERVICE_NAME, or service.name in OTEL_RESOURCE_ATTRIBUTES.
 	ServiceName string
 
-	// Endpoint is the OTLP collector endpoint.
+	// ServiceVersion is reported as service.version. Defaults to the main
+	// module version recorded in the binary, when it is a tagged release.
+	ServiceVersion string
+
+	// ServiceInstanceID is reported as service.instance.id and tells
+	// replicas apart. Defaults to a random UUID generated at start.
+	ServiceInstanceID string
+
+	// Environment is reported as deployment.environment.name, e.g. "production".
+	Environment string
+
+	// Detectors add resource attributes describing where the service runs.
+	// Defaults to DefaultDetectors: host, OS, process, container, Kubernetes
+	// and build info. Set an empty, non-nil slice to turn detection off, or
+	// list built-in and custom resource.Detector values to choose.
+	Detectors []resource.Detector
+
+	// Disabled turns off all signals while keeping the API usable: Start
+	// still returns a working router, Trace and Metric calls are no-ops and
+	// logs only go to the console. Defaults to OTEL_SDK_DISABLED.
+	Disabled bool
+
+	// DegradeOnError lets Start succeed when a signal cannot be exported,
+	// for example because no OTLP endpoint is set in this environment, TLS
+	// files cannot be read or an exporter cannot be created. That signal is
+	// turned off instead and a warning is logged. Other invalid settings
+	// still fail.
+	DegradeOnError bool
+
+	// Endpoint is the OTLP collector endpoint. Required when any signal
+	// uses ExporterOTLP without its own endpoint.
 	// For gRPC: "localhost:4317" (default port 4317)
 	// For HTTP: "localhost:4318" (default port 4318)
+	//
+	// URLs are also accepted. The scheme selects plaintext (http://) or TLS
+	// (https://), grpc:// and unix:///path/to.sock select gRPC, and a URL
+	// path such as "https://otel.example.com/otlp" selects OTLP/HTTP with
+	// "/otlp/v1/traces" etc. as the signal paths.
+	// Defaults to OTEL_EXPORTER_OTLP_ENDPOINT.
 	Endpoint string
 
 	// Protocol specifies the transport protocol (grpc or http).
-	// Defaults to ProtocolGRPC.
+	// Defaults to OTEL_EXPORTER_OTLP_PROTOCOL, or ProtocolGRPC.
 	Protocol Protocol
 
+	// Exporter selects where telemetry is sent: otlp, console, file or none.
+	// Defaults to ExporterOTLP. Endpoint is only required for OTLP.
+	Exporter Exporter
+
+	// ExportWriter is the destination of the console exporter.
+	// Defaults to os.Stdout.
+	ExportWriter io.Writer
+
+	// ExportFile is the path the file exporter appends JSON lines to.
+	ExportFile string
+
+	// Traces, Metrics and Logs override the exporter settings per signal,
+	// for example to send traces and metrics to different backends or to
+	// turn a signal off entirely.
+	Traces  SignalConfig
+	Metrics SignalConfig
+	Logs    SignalConfig
+
 	// Insecure determines whether to use a non-TLS connection.
 	// Defaults to true for local development.
 	Insecure bool
 
-	// LogLevel sets the minimum log level. Defaults to LevelInfo.
+	// TLS configures the CA, client certificate and server name used by the
+	// OTLP exporters when Insecure is false. Defaults to the system roots.
+	TLS TLSConfig
+
+	// Headers are sent with every OTLP export request, for example an API key
+	// required by a hosted backend. Per-signal Headers take precedence for
+	// the same key. Defaults to OTEL_EXPORTER_OTLP_HEADERS.
+	Headers map[string]string
+
+	// Timeout is the maximum time a single export request may take.
+	// Defaults to OTEL_EXPORTER_OTLP_TIMEOUT, or 10 seconds.
+	Timeout time.Duration
+
+	// Compression compresses export requests, which helps on slow or metered
+	// links. Defaults to OTEL_EXPORTER_OTLP_COMPRESSION, or CompressionNone.
+	Compression Compression
+
+	// Retry configures backoff for failed exports.
+	Retry RetryConfig
+
+	// Credentials supplies headers that change over time, such as OAuth2 or
+	// service account tokens. It is called for every export request and its
+	// headers override Headers. See TokenFile and NewOAuth2Credentials.
+	Credentials CredentialsProvider
+
+	// LogLevel sets the minimum log level. Defaults to OTEL_LOG_LEVEL, or LevelInfo.
 	LogLevel Level
 
+	// LogLevelSet makes LogLevel win over OTEL_LOG_LEVEL even when it is
+	// LevelInfo, which is also the zero value. WithLogLevel sets it.
+	LogLevelSet bool
+
+	// Console selects the format and destination of the local copy of
+	// every log record. Defaults to JSON lines on os.Stdout.
+	Console ConsoleConfig
+
 	// GlobalAttributes are added to all telemetry (traces, metrics, logs).
-	// Use this for team names, environment, region, etc.
+	// Use this for team names, environment, region, etc. Attributes from
+	// OTEL_RESOURCE_ATTRIBUTES are added for keys not set here.
 	GlobalAttributes map[string]string
 
+	// TraceBatch and LogBatch tune the batch processors for spans and log
+	// records. Default to the OTEL_BSP_* and OTEL_BLRP_* variables.
+	TraceBatch BatchConfig
+	LogBatch   BatchConfig
+
+	// MetricInterval is the time between metric exports.
+	// Defaults to OTEL_METRIC_EXPORT_INTERVAL, or 60 seconds.
+	MetricInterval time.Duration
+
+	// MetricTimeout is the maximum time a metric export may take.
+	// Defaults to OTEL_METRIC_EXPORT_TIMEOUT, or 30 seconds.
+	MetricTimeout time.Duration
+
+	// MetricTemporality selects cumulative or delta metric points, for
+	// backends that only accept one of them. Defaults to
+	// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE, or TemporalityCumulative.
+	MetricTemporality Temporality
+
+	// Views change the buckets, name, attributes or aggregation of matching
+	// metric instruments, or drop them. Views are fixed when the meter
+	// provider is created.
+	Views []View
+
+	// Cardinality limits the number of series each metric instrument may
+	// record, so that an attribute with unbounded values cannot flood the
+	// metrics backend. Defaults to 2000 series per instrument.
+	Cardinality CardinalityConfig
+
+	// Prometheus serves metrics for scraping, on the Gin router or on a
+	// separate listener. Disabled by default.
+	Prometheus PrometheusConfig
+
+	// Filter excludes requests such as health checks from tracing and HTTP
+	// server metrics.
+	Filter FilterConfig
+
+	// CaptureHeaders records selected request and response headers on
+	// server spans. None are recorded by default.
+	CaptureHeaders HeaderConfig
+
+	// Redaction removes sensitive values such as emails and tokens from
+	// spans, logs and metric attributes before they leave the process.
+	// Disabled by default.
+	Redaction RedactionConfig
+
+	// Limits bounds attribute, event and link counts on spans and log records.
+	Limits LimitsConfig
+
 	// ShutdownTimeout is the maximum time to wait for telemetry shutdown.
 	// Defaults to 10 seconds if not set.
 	ShutdownTimeout time.Duration
 
+	// Propagators lists the context propagation formats used to extract
+	// incoming trace headers and to inject outgoing ones. Unknown values cause
+	// Start to fail. Defaults to OTEL_PROPAGATORS, or tracecontext and baggage.
+	Propagators []Propagator
+
+	// Sampling configures head sampling for traces. Defaults to
+	// OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG, or parent-based always-on.
+	Sampling SamplingConfig
+
+	// TailSampling configures optional in-process tail-based sampling that
+	// keeps error and slow traces. Disabled by default.
+	TailSampling TailSamplingConfig
+
+	// Resource is merged over the default resource detected from the SDK and
+	// OTEL_RESOURCE_ATTRIBUTES. service.name and GlobalAttributes still win.
+	Resource *resource.Resource
+
+	// SpanProcessors are added to the tracer provider after the exporter's
+	// batch processor, for example to enrich spans or export them elsewhere.
+	// They are shut down with the provider.
+	SpanProcessors []sdktrace.SpanProcessor
+
 	// SetGlobalProvider controls whether to set the global OpenTelemetry provider.
 	// WARNING: Setting this to true makes the telemetry system use global state,
 	// which can cause issues with concurrent tests and multiple service instances.
@@ -54,29 +219,294 @@ type Config struct {
 	SetGlobalProvider bool
 }
 
+// validate fills unset fields from the environment and defaults. It checks
+// the whole configuration and returns all problems found, joined.
 func (c *Config) validate() error {
+	var errs []error
+
+	if !c.Disabled {
+		c.Disabled = envBool("OTEL_SDK_DISABLED")
+	}
+
+	// Add OTEL_RESOURCE_ATTRIBUTES for keys not set in GlobalAttributes
+	var envAttrs map[string]string
+	if v := os.Getenv("OTEL_RESOURCE_ATTRIBUTES"); v != "" {
+		var err error
+		envAttrs, err = parseHeaders(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gintelemetry: invalid OTEL_RESOURCE_ATTRIBUTES: %w", err))
+		}
+		merged := make(map[string]string, len(envAttrs)+len(c.GlobalAttributes))
+		for k, v := range envAttrs {
+			if k != "service.name" {
+				merged[k] = v
+			}
+		}
+		for k, v := range c.GlobalAttributes {
+			merged[k] = v
+		}
+		c.GlobalAttributes = merged
+	}
+
 	// Check OTEL_SERVICE_NAME if ServiceName not set
 	if c.ServiceName == "" {
 		c.ServiceName = os.Getenv("OTEL_SERVICE_NAME")
 	}
 	if c.ServiceName == "" {
-		return fmt.Errorf("gintelemetry: ServiceName is required")
+		c.ServiceName = envAttrs["service.name"]
+	}
+	if c.ServiceName == "" {
+		errs = append(errs, fmt.Errorf("gintelemetry: ServiceName is required"))
+	}
+	if c.ServiceInstanceID == "" {
+		c.ServiceInstanceID = newInstanceID()
+	}
+
+	// Check OTEL_LOG_LEVEL if LogLevel not set
+	if !c.logLevelSet() {
+		if v := os.Getenv("OTEL_LOG_LEVEL"); v != "" {
+			if err := c.LogLevel.UnmarshalText([]byte(strings.TrimSpace(v))); err != nil {
+				errs = append(errs, fmt.Errorf("gintelemetry: invalid OTEL_LOG_LEVEL %q", v))
+			}
+		}
+	}
+
+	if err := c.Console.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.Exporter == "" {
+		c.Exporter = ExporterOTLP
+	}
+	if err := validateExporter(c.Exporter); err != nil {
+		errs = append(errs, err)
 	}
 
 	// Check OTEL_EXPORTER_OTLP_ENDPOINT if Endpoint not set
 	if c.Endpoint == "" {
 		c.Endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
 	}
-	if c.Endpoint == "" {
-		return fmt.Errorf("gintelemetry: Endpoint is required")
+	endpoint, err := parseEndpoint(c.Endpoint)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("gintelemetry: invalid Endpoint %q: %w", c.Endpoint, err))
 	}
+	endpointValid := err == nil
 
-	// Set default protocol if not specified
+	// Check OTEL_EXPORTER_OTLP_PROTOCOL if Protocol not set, then the
+	// endpoint scheme
+	if c.Protocol == "" {
+		c.Protocol = Protocol(os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"))
+	}
+	if c.Protocol == "" {
+		c.Protocol = endpoint.protocol()
+	}
 	if c.Protocol == "" {
 		c.Protocol = ProtocolGRPC
 	}
+	if protocol, err := parseProtocol(string(c.Protocol)); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.Protocol = protocol
+	}
+
+	// validate fills in a default MinVersion, so note whether TLS was
+	// configured before it runs.
+	tlsSet := c.TLS.isSet()
+	if c.Insecure {
+		if tlsSet {
+			errs = append(errs, fmt.Errorf("gintelemetry: TLS settings cannot be used with Insecure"))
+		}
+	} else if err := c.TLS.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	tlsSet = tlsSet || c.TLS.CAFile != "" || c.TLS.CertFile != ""
+
+	// Check OTEL_EXPORTER_OTLP_HEADERS if Headers not set
+	if c.Headers == nil {
+		if v := os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"); v != "" {
+			h, err := parseHeaders(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("gintelemetry: invalid OTEL_EXPORTER_OTLP_HEADERS: %w", err))
+			}
+			c.Headers = h
+		}
+	}
+
+	// Check OTEL_EXPORTER_OTLP_TIMEOUT if Timeout not set
+	if c.Timeout == 0 {
+		d, err := envMillis("OTEL_EXPORTER_OTLP_TIMEOUT")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.Timeout = d
+	}
+	if c.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("gintelemetry: Timeout cannot be negative"))
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 10 * time.Second
+	}
+
+	// Check OTEL_EXPORTER_OTLP_COMPRESSION if Compression not set
+	if c.Compression == "" {
+		c.Compression = Compression(os.Getenv("OTEL_EXPORTER_OTLP_COMPRESSION"))
+	}
+	if c.Compression == "" {
+		c.Compression = CompressionNone
+	}
+	if compression, err := parseCompression(string(c.Compression)); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.Compression = compression
+	}
+
+	if err := c.Retry.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	signals := []struct {
+		name string
+		sig  *SignalConfig
+	}{
+		{"TRACES", &c.Traces},
+		{"METRICS", &c.Metrics},
+		{"LOGS", &c.Logs},
+	}
+	for _, sg := range signals {
+		if err := signalEnv(sg.name, sg.sig); err != nil {
+			errs = append(errs, err)
+		}
+		name := strings.ToLower(sg.name)
+		if sg.sig.Protocol != "" {
+			if protocol, err := parseProtocol(string(sg.sig.Protocol)); err != nil {
+				errs = append(errs, err)
+			} else {
+				sg.sig.Protocol = protocol
+			}
+		}
+		signalEndpointValid := endpointValid
+		if sg.sig.Endpoint != "" {
+			_, err := parseEndpoint(sg.sig.Endpoint)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("gintelemetry: invalid %s endpoint %q: %w", name, sg.sig.Endpoint, err))
+			}
+			signalEndpointValid = err == nil
+		}
+		s := c.signal(name, *sg.sig)
+		if s.disabled {
+			continue
+		}
+		if s.exporter != c.Exporter {
+			if err := validateExporter(s.exporter); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if s.exporter == ExporterFile && s.file == "" {
+			errs = append(errs, fmt.Errorf("gintelemetry: ExportFile is required for the file exporter (%s)", name))
+		}
+		if s.exporter != ExporterOTLP || !signalEndpointValid {
+			continue
+		}
+		if s.endpoint == "" {
+			if !c.DegradeOnError {
+				errs = append(errs, fmt.Errorf("gintelemetry: Endpoint is required for %s", name))
+			}
+		} else if err := s.checkEndpoint(name, c.Insecure, tlsSet); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Check OTEL_PROPAGATORS if Propagators not set
+	if len(c.Propagators) == 0 {
+		c.Propagators = parsePropagators(os.Getenv("OTEL_PROPAGATORS"))
+	}
+	if len(c.Propagators) == 0 {
+		c.Propagators = defaultPropagators
+	}
+	for _, p := range c.Propagators {
+		if err := validatePropagator(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := c.Sampling.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.TailSampling.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.TraceBatch.validate("BSP", 5*time.Second); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.LogBatch.validate("BLRP", time.Second); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check OTEL_METRIC_EXPORT_INTERVAL and OTEL_METRIC_EXPORT_TIMEOUT if not set
+	if c.MetricInterval == 0 {
+		if c.MetricInterval, err = envMillis("OTEL_METRIC_EXPORT_INTERVAL"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.MetricInterval == 0 {
+		c.MetricInterval = time.Minute
+	}
+	if c.MetricTimeout == 0 {
+		if c.MetricTimeout, err = envMillis("OTEL_METRIC_EXPORT_TIMEOUT"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.MetricTimeout == 0 {
+		c.MetricTimeout = 30 * time.Second
+	}
+	if c.MetricInterval < 0 || c.MetricTimeout < 0 {
+		errs = append(errs, fmt.Errorf("gintelemetry: MetricInterval and MetricTimeout cannot be negative"))
+	}
 
-	return nil
+	// Check OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE if MetricTemporality not set
+	if c.MetricTemporality == "" {
+		c.MetricTemporality = Temporality(os.Getenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"))
+	}
+	if c.MetricTemporality == "" {
+		c.MetricTemporality = TemporalityCumulative
+	}
+	if temporality, err := parseTemporality(string(c.MetricTemporality)); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.MetricTemporality = temporality
+	}
+
+	c.CaptureHeaders.validate()
+
+	if err := c.Redaction.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.Filter.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.Prometheus.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.Cardinality.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, v := range c.Views {
+		if err := v.validate(i); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := c.Limits.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
 
 func (c *Config) getLogLevel() Level {
@@ -86,6 +516,11 @@ func (c *Config) getLogLevel() Level {
 	return LevelInfo
 }
 
+// logLevelSet reports whether LogLevel was set explicitly.
+func (c *Config) logLevelSet() bool {
+	return c.LogLevel != 0 || c.LogLevelSet
+}
+
 func (c *Config) getShutdownTimeout() time.Duration {
 	if c.ShutdownTimeout > 0 {
 		return c.ShutdownTimeout
@@ -0,0 +1,47 @@
+package gintelemetry
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestConfig_LogLevelPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		env  string
+		want slog.Level
+	}{
+		{name: "default", want: slog.LevelInfo},
+		{name: "env", env: "debug", want: slog.LevelDebug},
+		{name: "unset info does not beat env", cfg: Config{LogLevel: LevelInfo}, env: "debug", want: slog.LevelDebug},
+		{name: "explicit info beats env", cfg: Config{LogLevel: LevelInfo, LogLevelSet: true}, env: "debug", want: slog.LevelInfo},
+		{name: "explicit error beats env", cfg: Config{LogLevel: LevelError}, env: "debug", want: slog.LevelError},
+		{name: "slog level", cfg: Config{LogLevel: slog.LevelWarn}, env: "debug", want: slog.LevelWarn},
+		{name: "option", cfg: configFrom([]Option{WithLogLevel(LevelInfo)}), env: "debug", want: slog.LevelInfo},
+		{name: "env with offset", env: "WARN+2", want: slog.LevelWarn + 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_LOG_LEVEL", tt.env)
+			cfg := tt.cfg
+			cfg.ServiceName = "test"
+			cfg.Exporter = ExporterNone
+			if err := cfg.validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := cfg.getLogLevel(); got != tt.want {
+				t.Errorf("getLogLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_InvalidLogLevel(t *testing.T) {
+	t.Setenv("OTEL_LOG_LEVEL", "loud")
+	cfg := Config{ServiceName: "test", Exporter: ExporterNone}
+	if err := cfg.validate(); err == nil {
+		t.Error("validate() accepted OTEL_LOG_LEVEL=loud")
+	}
+}
@@ -0,0 +1,68 @@
+package gintelemetry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// lockedWriter serializes writes from the exporters of different signals
+// that share one destination.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+func (l *lockedWriter) writeLine(line []byte) error {
+	_, err := l.Write(append(line, '\n'))
+	return err
+}
+
+// exportWriters opens and shares the destinations of the console and file
+// exporters so each path is opened once.
+type exportWriters struct {
+	writers map[string]*lockedWriter
+	files   []*os.File
+}
+
+func (e *exportWriters) get(path string, fallback io.Writer) (*lockedWriter, error) {
+	if e.writers == nil {
+		e.writers = make(map[string]*lockedWriter)
+	}
+	if path == "" {
+		if w, ok := e.writers[""]; ok {
+			return w, nil
+		}
+		w := &lockedWriter{w: fallback}
+		e.writers[""] = w
+		return w, nil
+	}
+	if w, ok := e.writers[path]; ok {
+		return w, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+	e.files = append(e.files, f)
+	w := &lockedWriter{w: f}
+	e.writers[path] = w
+	return w, nil
+}
+
+func (e *exportWriters) Close() error {
+	var firstErr error
+	for _, f := range e.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
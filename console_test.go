@@ -0,0 +1,75 @@
+package gintelemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestFileExporter_WritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	writers := &exportWriters{}
+	t.Cleanup(func() { _ = writers.Close() })
+
+	exporter, err := newTraceExporter(context.Background(), signalSettings{exporter: ExporterFile, file: path}, writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	for _, name := range []string{"first", "second"} {
+		_, span := tp.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("file has %d lines, want 2:\n%s", len(lines), data)
+	}
+	for _, line := range lines {
+		var span struct{ Name string }
+		if err := json.Unmarshal([]byte(line), &span); err != nil || span.Name == "" {
+			t.Errorf("line %q is not a JSON span: %v", line, err)
+		}
+	}
+}
+
+// Values JSON cannot encode must fail the export instead of writing an
+// empty line.
+func TestConsoleExporter_ReturnsEncodingErrors(t *testing.T) {
+	var buf bytes.Buffer
+	writers := &exportWriters{}
+	s := signalSettings{exporter: ExporterConsole, writer: &buf, temporality: TemporalityCumulative}
+	exporter, err := newMetricExporter(context.Background(), s, writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rm := &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{
+		Metrics: []metricdata.Metrics{{
+			Name: "ratio",
+			Data: metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{Value: math.NaN()}}},
+		}},
+	}}}
+	if err := exporter.Export(context.Background(), rm); err == nil {
+		t.Errorf("Export() of NaN succeeded and wrote %q", buf.String())
+	}
+	if got := exporter.Temporality(sdkmetric.InstrumentKindCounter); got != metricdata.CumulativeTemporality {
+		t.Errorf("Temporality() = %v, want cumulative", got)
+	}
+}
@@ -0,0 +1,258 @@
+package gintelemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConsoleFormat selects how logs are written to the console.
+type ConsoleFormat string
+
+const (
+	// ConsoleJSON writes one JSON object per line (default).
+	ConsoleJSON ConsoleFormat = "json"
+
+	// ConsoleText writes logfmt-style key=value lines.
+	ConsoleText ConsoleFormat = "text"
+
+	// ConsolePretty writes colored, aligned lines for reading in a terminal
+	// during development.
+	ConsolePretty ConsoleFormat = "pretty"
+
+	// ConsoleOff writes no console output. Logs are still exported.
+	ConsoleOff ConsoleFormat = "off"
+)
+
+// ConsoleConfig configures the local copy of every log record, written in
+// addition to the log exporter.
+//
+// Example:
+//
+//	Console: gintelemetry.ConsoleConfig{Format: gintelemetry.ConsolePretty}
+type ConsoleConfig struct {
+	// Format selects JSON, text, pretty or no output. Defaults to ConsoleJSON.
+	Format ConsoleFormat
+
+	// Writer is the destination. Defaults to os.Stdout.
+	Writer io.Writer
+
+	// NoColor turns off colors in the pretty format. Colors are also off
+	// when NO_COLOR is set or Writer is not a terminal.
+	NoColor bool
+}
+
+func (c *ConsoleConfig) validate() error {
+	switch c.Format {
+	case "":
+		c.Format = ConsoleJSON
+	case ConsoleJSON, ConsoleText, ConsolePretty, ConsoleOff:
+	default:
+		return fmt.Errorf("gintelemetry: unsupported console format %q", c.Format)
+	}
+	if c.Writer == nil {
+		c.Writer = os.Stdout
+	}
+	return nil
+}
+
+// handler returns the console handler, or nil when the console is off.
+func (c ConsoleConfig) handler(level Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	switch c.Format {
+	case ConsoleOff:
+		return nil
+	case ConsoleText:
+		return slog.NewTextHandler(c.Writer, opts)
+	case ConsolePretty:
+		return &prettyHandler{
+			out:   &lockedWriter{w: c.Writer},
+			level: level,
+			color: !c.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(c.Writer),
+		}
+	}
+	return slog.NewJSONHandler(c.Writer, opts)
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ANSI escape sequences used by the pretty format.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiFaint  = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// prettyMessageWidth pads messages so that the fields after them line up.
+const prettyMessageWidth = 40
+
+// prettyHandler writes records as
+//
+//	15:04:05.000 INF 4bf92f35 message                   key=value key=value
+//
+// where 4bf92f35 is the start of the trace ID, left blank outside a trace.
+type prettyHandler struct {
+	out   *lockedWriter
+	level Level
+	color bool
+	attrs []byte // preformatted attributes from WithAttrs
+	group string // key prefix from WithGroup, e.g. "request."
+}
+
+func (h *prettyHandler) Enabled(ctx context.Context, level Level) bool {
+	return level >= h.level
+}
+
+func (h *prettyHandler) Handle(ctx context.Context, r slog.Record) error {
+	buf := make([]byte, 0, 256)
+	if !r.Time.IsZero() {
+		buf = h.paint(buf, ansiFaint, r.Time.Format("15:04:05.000"))
+		buf = append(buf, ' ')
+	}
+	label, color := levelLabel(r.Level)
+	buf = h.paint(buf, color, label)
+	buf = append(buf, ' ')
+
+	traceID := strings.Repeat(" ", 8)
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		traceID = sc.TraceID().String()[:8]
+	}
+	buf = h.paint(buf, ansiBlue, traceID)
+	buf = append(buf, ' ')
+
+	// Quote messages with line breaks or control characters so that a
+	// message cannot forge further log lines or terminal escapes.
+	msg := r.Message
+	if needsEscaping(msg) {
+		msg = strconv.Quote(msg)
+	}
+	buf = append(buf, msg...)
+	if r.NumAttrs() > 0 || len(h.attrs) > 0 {
+		if pad := prettyMessageWidth - len(msg); pad > 0 {
+			buf = append(buf, strings.Repeat(" ", pad)...)
+		}
+	}
+	buf = append(buf, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, h.group, a)
+		return true
+	})
+	return h.out.writeLine(buf)
+}
+
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = append([]byte(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = h.appendAttr(h2.attrs, h.group, a)
+	}
+	return &h2
+}
+
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// appendAttr appends " key=value", flattening groups into dotted keys.
+func (h *prettyHandler) appendAttr(buf []byte, prefix string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range a.Value.Group() {
+			buf = h.appendAttr(buf, prefix, g)
+		}
+		return buf
+	}
+	key := prefix + a.Key
+	if needsQuoting(key) {
+		key = strconv.Quote(key)
+	}
+	buf = append(buf, ' ')
+	buf = h.paint(buf, ansiCyan, key+"=")
+	value := a.Value.String()
+	if a.Value.Kind() == slog.KindTime {
+		value = a.Value.Time().Format(time.RFC3339Nano)
+	}
+	if needsQuoting(value) {
+		value = strconv.Quote(value)
+	}
+	return append(buf, value...)
+}
+
+// paint appends s, wrapped in color when colors are on.
+func (h *prettyHandler) paint(buf []byte, color, s string) []byte {
+	if !h.color {
+		return append(buf, s...)
+	}
+	buf = append(buf, color...)
+	buf = append(buf, s...)
+	return append(buf, ansiReset...)
+}
+
+func levelLabel(level Level) (string, string) {
+	switch {
+	case level >= LevelError:
+		return "ERR", ansiRed
+	case level >= LevelWarn:
+		return "WRN", ansiYellow
+	case level >= LevelInfo:
+		return "INF", ansiGreen
+	}
+	return "DBG", ansiFaint
+}
+
+// needsEscaping reports whether s has characters other than spaces that
+// would break the line, such as newlines or escape sequences.
+func needsEscaping(s string) bool {
+	for _, r := range s {
+		if r != ' ' && (unicode.IsSpace(r) || !unicode.IsPrint(r)) {
+			return true
+		}
+	}
+	return false
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
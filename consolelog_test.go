@@ -0,0 +1,239 @@
+package gintelemetry
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordTime is the time of every test record, formatted as 15:04:05.123.
+var recordTime = time.Date(2024, 5, 1, 15, 4, 5, 123e6, time.UTC)
+
+// newPrettyTest returns a pretty handler without colors writing to buf.
+func newPrettyTest(t *testing.T, buf *bytes.Buffer) slog.Handler {
+	t.Helper()
+	cfg := ConsoleConfig{Format: ConsolePretty, Writer: buf}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	return cfg.handler(LevelDebug)
+}
+
+func handle(t *testing.T, h slog.Handler, ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	t.Helper()
+	r := slog.NewRecord(recordTime, level, msg, 0)
+	r.AddAttrs(attrs...)
+	if err := h.Handle(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrettyHandler_Line(t *testing.T) {
+	traceCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6},
+		SpanID:  trace.SpanID{1},
+	}))
+	blank := strings.Repeat(" ", 8)
+	pad := func(msg string) string { return msg + strings.Repeat(" ", prettyMessageWidth-len(msg)) }
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		level slog.Level
+		msg   string
+		attrs []slog.Attr
+		want  string
+	}{
+		{
+			name:  "message only",
+			ctx:   context.Background(),
+			level: slog.LevelInfo,
+			msg:   "started",
+			want:  "15:04:05.123 INF " + blank + " started\n",
+		},
+		{
+			name:  "trace ID and attributes",
+			ctx:   traceCtx,
+			level: slog.LevelError,
+			msg:   "order failed",
+			attrs: []slog.Attr{slog.String("order.id", "42"), slog.Int("attempt", 3)},
+			want:  "15:04:05.123 ERR 4bf92f35 " + pad("order failed") + " order.id=42 attempt=3\n",
+		},
+		{
+			name:  "levels",
+			ctx:   context.Background(),
+			level: slog.LevelDebug,
+			msg:   "tick",
+			attrs: []slog.Attr{slog.Bool("ok", true)},
+			want:  "15:04:05.123 DBG " + blank + " " + pad("tick") + " ok=true\n",
+		},
+		{
+			name:  "quoted values",
+			ctx:   context.Background(),
+			level: slog.LevelWarn,
+			msg:   "slow",
+			attrs: []slog.Attr{slog.String("query", "select 1"), slog.String("empty", ""), slog.String("expr", "a=b")},
+			want:  "15:04:05.123 WRN " + blank + " " + pad("slow") + ` query="select 1" empty="" expr="a=b"` + "\n",
+		},
+		{
+			name:  "message with a newline",
+			ctx:   context.Background(),
+			level: slog.LevelInfo,
+			msg:   "login\n15:04:05.123 INF          admin logged in",
+			want:  "15:04:05.123 INF " + blank + ` "login\n15:04:05.123 INF          admin logged in"` + "\n",
+		},
+		{
+			name:  "message with an escape sequence",
+			ctx:   context.Background(),
+			level: slog.LevelInfo,
+			msg:   "\x1b[2Jcleared",
+			want:  "15:04:05.123 INF " + blank + ` "\x1b[2Jcleared"` + "\n",
+		},
+		{
+			name:  "key and value with newlines",
+			ctx:   context.Background(),
+			level: slog.LevelInfo,
+			msg:   "hi",
+			attrs: []slog.Attr{slog.String("a\nb", "c\nd")},
+			want:  "15:04:05.123 INF " + blank + " " + pad("hi") + ` "a\nb"="c\nd"` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handle(t, newPrettyTest(t, &buf), tt.ctx, tt.level, tt.msg, tt.attrs...)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("line =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyHandler_GroupsAndAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	h := newPrettyTest(t, &buf).
+		WithAttrs([]slog.Attr{slog.String("service", "api")}).
+		WithGroup("request").
+		WithAttrs([]slog.Attr{slog.Int("id", 7)}).
+		WithGroup("")
+	handle(t, h, context.Background(), slog.LevelInfo, "done",
+		slog.Group("user", slog.String("name", "ann")),
+		slog.Group("", slog.String("inline", "yes")),
+		slog.Attr{})
+
+	want := " service=api request.id=7 request.user.name=ann request.inline=yes\n"
+	if got := buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("line = %q, want suffix %q", got, want)
+	}
+}
+
+func TestPrettyHandler_Level(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := ConsoleConfig{Format: ConsolePretty, Writer: &buf}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(cfg.handler(LevelWarn))
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if got := buf.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "shown") {
+		t.Errorf("output = %q, want only the warning", got)
+	}
+}
+
+func TestConsoleConfig_Color(t *testing.T) {
+	tty, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = tty.Close() })
+	if !isTerminal(tty) {
+		t.Skipf("%s is not a character device", os.DevNull)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     ConsoleConfig
+		noColor string
+		want    bool
+	}{
+		{name: "terminal", cfg: ConsoleConfig{Writer: tty}, want: true},
+		{name: "NoColor", cfg: ConsoleConfig{Writer: tty, NoColor: true}},
+		{name: "NO_COLOR", cfg: ConsoleConfig{Writer: tty}, noColor: "1"},
+		{name: "not a terminal", cfg: ConsoleConfig{Writer: &bytes.Buffer{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			tt.cfg.Format = ConsolePretty
+			if got := tt.cfg.handler(LevelInfo).(*prettyHandler).color; got != tt.want {
+				t.Errorf("color = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	h := &prettyHandler{out: &lockedWriter{w: &buf}, color: true}
+	handle(t, h, context.Background(), slog.LevelError, "boom", slog.Int("code", 500))
+	for _, want := range []string{ansiRed + "ERR" + ansiReset, ansiCyan + "code=" + ansiReset} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("colored line = %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+func TestConsoleConfig_Formats(t *testing.T) {
+	tests := []struct {
+		format ConsoleFormat
+		want   string
+	}{
+		{format: "", want: `"msg":"hello"`},
+		{format: ConsoleJSON, want: `"msg":"hello"`},
+		{format: ConsoleText, want: "msg=hello"},
+		{format: ConsolePretty, want: " hello\n"},
+		{format: ConsoleOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			cfg := ConsoleConfig{Format: tt.format, Writer: &buf}
+			if err := cfg.validate(); err != nil {
+				t.Fatal(err)
+			}
+			applyLevelFilter(nil, LevelInfo, cfg).Info("hello")
+			if tt.want == "" {
+				if buf.Len() != 0 {
+					t.Errorf("output = %q, want none", buf.String())
+				}
+				return
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleConfig_Validate(t *testing.T) {
+	cfg := ConsoleConfig{}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != ConsoleJSON || cfg.Writer != os.Stdout {
+		t.Errorf("defaults = %+v, want JSON on os.Stdout", cfg)
+	}
+
+	cfg = ConsoleConfig{Format: "yaml"}
+	if err := cfg.validate(); err == nil {
+		t.Error("validate() accepted an unknown format")
+	}
+}
@@ -0,0 +1,213 @@
+package gintelemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies headers, such as an Authorization bearer
+// token, that are attached to every export request. Headers is called once
+// per export, so implementations should cache credentials and refresh them
+// before they expire.
+type CredentialsProvider interface {
+	Headers(ctx context.Context) (map[string]string, error)
+}
+
+// TokenFile returns a CredentialsProvider that sends the contents of path as
+// an "Authorization: Bearer" token. The file is re-read when it changes,
+// which suits projected Kubernetes service account tokens.
+func TokenFile(path string) CredentialsProvider {
+	return &tokenFileCredentials{path: path}
+}
+
+type tokenFileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+func (c *tokenFileCredentials) Headers(context.Context) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("gintelemetry: failed to read token file: %w", err)
+	}
+	if c.token == "" || !info.ModTime().Equal(c.modTime) {
+		data, err := os.ReadFile(c.path)
+		if err != nil {
+			return nil, fmt.Errorf("gintelemetry: failed to read token file: %w", err)
+		}
+		c.token = strings.TrimSpace(string(data))
+		c.modTime = info.ModTime()
+	}
+	return map[string]string{"Authorization": "Bearer " + c.token}, nil
+}
+
+// OAuth2Config configures the OAuth2 client-credentials grant.
+type OAuth2Config struct {
+	// TokenURL is the token endpoint of the authorization server.
+	TokenURL string
+
+	// ClientID and ClientSecret identify this service.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are requested with each token.
+	Scopes []string
+
+	// EarlyRefresh is how long before expiry a token is refreshed.
+	// Defaults to 1 minute. Tokens without an expiry are refreshed hourly.
+	EarlyRefresh time.Duration
+
+	// HTTPClient is used to call TokenURL. Defaults to a client with a
+	// 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// NewOAuth2Credentials returns a CredentialsProvider that obtains bearer
+// tokens with the OAuth2 client-credentials grant and refreshes them before
+// they expire.
+func NewOAuth2Credentials(cfg OAuth2Config) CredentialsProvider {
+	if cfg.EarlyRefresh <= 0 {
+		cfg.EarlyRefresh = time.Minute
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &oauth2Credentials{cfg: cfg}
+}
+
+type oauth2Credentials struct {
+	cfg OAuth2Config
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (c *oauth2Credentials) Headers(ctx context.Context) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.token == "" || now.Add(c.cfg.EarlyRefresh).After(c.expiry) {
+		token, expiry, err := c.fetch(ctx)
+		switch {
+		case err == nil:
+			c.token, c.expiry = token, expiry
+		case c.token != "" && now.Before(c.expiry):
+			// Keep using the current token until it actually expires.
+		default:
+			return nil, err
+		}
+	}
+	return map[string]string{"Authorization": "Bearer " + c.token}, nil
+}
+
+func (c *oauth2Credentials) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("gintelemetry: failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("gintelemetry: token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("gintelemetry: token request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, fmt.Errorf("gintelemetry: invalid token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("gintelemetry: token response has no access_token")
+	}
+
+	lifetime := time.Hour
+	if body.ExpiresIn > 0 {
+		lifetime = time.Duration(body.ExpiresIn) * time.Second
+	}
+	return body.AccessToken, time.Now().Add(lifetime), nil
+}
+
+// grpcCredentials adapts a CredentialsProvider to gRPC per-RPC credentials.
+type grpcCredentials struct {
+	provider   CredentialsProvider
+	requireTLS bool
+}
+
+func (g grpcCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	headers, err := g.provider.Headers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	md := make(map[string]string, len(headers))
+	for k, v := range headers {
+		md[strings.ToLower(k)] = v
+	}
+	return md, nil
+}
+
+func (g grpcCredentials) RequireTransportSecurity() bool {
+	return g.requireTLS
+}
+
+// credentialsTransport adds provider headers to each HTTP export request.
+type credentialsTransport struct {
+	provider CredentialsProvider
+	base     http.RoundTripper
+}
+
+func (t *credentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers, err := t.provider.Headers(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newCredentialsClient builds the HTTP client used by the OTLP/HTTP exporters
+// when a CredentialsProvider is configured. The exporters ignore their TLS
+// and timeout options when given a client, so those are applied here.
+func newCredentialsClient(s signalSettings) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if s.tls != nil {
+		base.TLSClientConfig = s.tls.Clone()
+	}
+	timeout := s.timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &http.Client{
+		Transport: &credentialsTransport{provider: s.credentials, base: base},
+		Timeout:   timeout,
+	}
+}
@@ -0,0 +1,96 @@
+package gintelemetry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// endpointURL is an OTLP endpoint split into the parts the exporters take
+// separately. Endpoints may be bare "host:port" values or URLs with an
+// http, https, grpc or unix scheme.
+type endpointURL struct {
+	scheme  string // "", "http", "https", "grpc" or "unix"
+	address string // host:port, or unix:///path for sockets
+	path    string // URL path without trailing slash, "" for none
+}
+
+func parseEndpoint(raw string) (endpointURL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return endpointURL{}, nil
+	}
+	if !strings.Contains(raw, "://") && !strings.HasPrefix(strings.ToLower(raw), "unix:") {
+		if strings.ContainsAny(raw, "/?#") {
+			return endpointURL{}, errors.New("a URL path requires a scheme such as https://")
+		}
+		return endpointURL{address: raw}, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return endpointURL{}, err
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return endpointURL{}, errors.New("query and fragment are not supported")
+	}
+
+	ep := endpointURL{scheme: strings.ToLower(u.Scheme)}
+	switch ep.scheme {
+	case "http", "https", "grpc":
+		if u.Host == "" {
+			return endpointURL{}, errors.New("missing host")
+		}
+		ep.address = u.Host
+		ep.path = strings.TrimSuffix(u.Path, "/")
+	case "unix":
+		if u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+			return endpointURL{}, errors.New("unix endpoints need an absolute socket path, e.g. unix:///var/run/otel.sock")
+		}
+		ep.address = "unix://" + u.Path
+	default:
+		return endpointURL{}, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	return ep, nil
+}
+
+// protocol returns the protocol implied by the endpoint, or "" when the
+// endpoint works with either. A URL path is only meaningful for OTLP/HTTP.
+func (e endpointURL) protocol() Protocol {
+	switch {
+	case e.scheme == "grpc" || e.scheme == "unix":
+		return ProtocolGRPC
+	case e.path != "":
+		return ProtocolHTTP
+	}
+	return ""
+}
+
+// insecure reports whether the exporter should use a plaintext connection,
+// following the scheme when it decides and def otherwise.
+func (e endpointURL) insecure(def bool) bool {
+	switch e.scheme {
+	case "http", "unix":
+		return true
+	case "https":
+		return false
+	}
+	return def
+}
+
+// checkEndpoint reports settings that contradict the endpoint scheme, where
+// insecure and tlsSet describe the top-level Config.
+func (s signalSettings) checkEndpoint(name string, insecure, tlsSet bool) error {
+	switch {
+	case s.protocol == ProtocolHTTP && (s.scheme == "grpc" || s.scheme == "unix"):
+		return fmt.Errorf("gintelemetry: %s:// endpoint for %s requires the grpc protocol", s.scheme, name)
+	case s.protocol == ProtocolGRPC && s.path != "":
+		return fmt.Errorf("gintelemetry: endpoint path %q for %s requires the http protocol", s.path, name)
+	case s.scheme == "https" && insecure:
+		return fmt.Errorf("gintelemetry: https:// endpoint for %s cannot be used with Insecure", name)
+	case s.scheme == "http" && tlsSet:
+		return fmt.Errorf("gintelemetry: http:// endpoint for %s cannot be used with TLS settings", name)
+	}
+	return nil
+}
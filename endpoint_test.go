@@ -0,0 +1,156 @@
+package gintelemetry
+
+import "testing"
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    endpointURL
+		wantErr bool
+	}{
+		{raw: ""},
+		{raw: "collector:4317", want: endpointURL{address: "collector:4317"}},
+		{raw: " collector:4317 ", want: endpointURL{address: "collector:4317"}},
+		{raw: "https://collector:4318", want: endpointURL{scheme: "https", address: "collector:4318"}},
+		{raw: "HTTP://collector:4318/", want: endpointURL{scheme: "http", address: "collector:4318"}},
+		{raw: "https://gateway/otlp/", want: endpointURL{scheme: "https", address: "gateway", path: "/otlp"}},
+		{raw: "grpc://collector:4317", want: endpointURL{scheme: "grpc", address: "collector:4317"}},
+		{raw: "unix:///var/run/otel.sock", want: endpointURL{scheme: "unix", address: "unix:///var/run/otel.sock"}},
+		{raw: "collector:4318/otlp", wantErr: true},
+		{raw: "https://collector:4318?x=1", wantErr: true},
+		{raw: "https://collector:4318#frag", wantErr: true},
+		{raw: "https:///v1/traces", wantErr: true},
+		{raw: "unix://host/otel.sock", wantErr: true},
+		{raw: "unix:otel.sock", wantErr: true},
+		{raw: "ftp://collector", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseEndpoint(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseEndpoint(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseEndpoint(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointURL_ProtocolAndInsecure(t *testing.T) {
+	tests := []struct {
+		raw          string
+		wantProtocol Protocol
+		wantInsecure bool // with an insecure default of false
+	}{
+		{raw: "collector:4317"},
+		{raw: "http://collector:4318", wantInsecure: true},
+		{raw: "https://collector:4318"},
+		{raw: "https://gateway/otlp", wantProtocol: ProtocolHTTP},
+		{raw: "grpc://collector:4317", wantProtocol: ProtocolGRPC},
+		{raw: "unix:///var/run/otel.sock", wantProtocol: ProtocolGRPC, wantInsecure: true},
+	}
+
+	for _, tt := range tests {
+		ep, err := parseEndpoint(tt.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ep.protocol(); got != tt.wantProtocol {
+			t.Errorf("%q protocol() = %q, want %q", tt.raw, got, tt.wantProtocol)
+		}
+		if got := ep.insecure(false); got != tt.wantInsecure {
+			t.Errorf("%q insecure(false) = %v, want %v", tt.raw, got, tt.wantInsecure)
+		}
+	}
+}
+
+func TestConfig_SignalEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantEndpoint string
+		wantPath     string
+		wantProtocol Protocol
+		wantInsecure bool
+	}{
+		{
+			name:         "top-level path is a prefix",
+			cfg:          Config{Endpoint: "https://gateway/otlp"},
+			wantEndpoint: "gateway",
+			wantPath:     "/otlp/v1/traces",
+			wantProtocol: ProtocolHTTP,
+		},
+		{
+			name:         "per-signal endpoint path is the full path",
+			cfg:          Config{Endpoint: "collector:4317", Traces: SignalConfig{Endpoint: "http://tracing:4318/custom"}},
+			wantEndpoint: "tracing:4318",
+			wantPath:     "/custom",
+			wantProtocol: ProtocolHTTP,
+			wantInsecure: true,
+		},
+		{
+			name:         "per-signal endpoint without a path is used as-is",
+			cfg:          Config{Endpoint: "https://gateway/otlp", Traces: SignalConfig{Endpoint: "http://tracing:4318"}},
+			wantEndpoint: "tracing:4318",
+			wantPath:     "/",
+			wantProtocol: ProtocolHTTP,
+			wantInsecure: true,
+		},
+		{
+			name:         "bare per-signal endpoint keeps the default path",
+			cfg:          Config{Endpoint: "https://gateway/otlp", Traces: SignalConfig{Endpoint: "tracing:4318"}},
+			wantEndpoint: "tracing:4318",
+			wantProtocol: ProtocolHTTP,
+		},
+		{
+			name:         "Path overrides the endpoint path",
+			cfg:          Config{Endpoint: "https://gateway/otlp", Traces: SignalConfig{Path: "spans"}},
+			wantEndpoint: "gateway",
+			wantPath:     "/spans",
+			wantProtocol: ProtocolHTTP,
+		},
+		{
+			name:         "bare endpoint",
+			cfg:          Config{Endpoint: "collector:4317"},
+			wantEndpoint: "collector:4317",
+			wantProtocol: ProtocolGRPC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ServiceName = "test"
+			if err := tt.cfg.validate(); err != nil {
+				t.Fatal(err)
+			}
+			s := tt.cfg.signal("traces", tt.cfg.Traces)
+			if s.endpoint != tt.wantEndpoint || s.path != tt.wantPath || s.protocol != tt.wantProtocol || s.insecure != tt.wantInsecure {
+				t.Errorf("traces = {endpoint:%q path:%q protocol:%q insecure:%v}, want {endpoint:%q path:%q protocol:%q insecure:%v}",
+					s.endpoint, s.path, s.protocol, s.insecure, tt.wantEndpoint, tt.wantPath, tt.wantProtocol, tt.wantInsecure)
+			}
+		})
+	}
+}
+
+func TestConfig_EndpointConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "grpc scheme with http protocol", cfg: Config{Endpoint: "grpc://collector:4317", Protocol: ProtocolHTTP}},
+		{name: "path with grpc protocol", cfg: Config{Endpoint: "https://gateway/otlp", Protocol: ProtocolGRPC}},
+		{name: "https with Insecure", cfg: Config{Endpoint: "https://collector:4318", Insecure: true}},
+		{name: "http with TLS", cfg: Config{Endpoint: "http://collector:4318", TLS: TLSConfig{ServerName: "collector"}}},
+		{name: "invalid endpoint", cfg: Config{Endpoint: "collector:4318/otlp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ServiceName = "test"
+			if err := tt.cfg.validate(); err == nil {
+				t.Error("validate() accepted a conflicting endpoint")
+			}
+		})
+	}
+}
@@ -0,0 +1,665 @@
+package gintelemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Exporter selects where a telemetry signal is sent.
+type Exporter string
+
+const (
+	// ExporterOTLP sends telemetry to an OTLP collector (default).
+	ExporterOTLP Exporter = "otlp"
+
+	// ExporterConsole writes telemetry as indented JSON to
+	// Config.ExportWriter, or stdout when unset. No collector is required.
+	ExporterConsole Exporter = "console"
+
+	// ExporterFile appends telemetry as JSON lines to Config.ExportFile.
+	ExporterFile Exporter = "file"
+
+	// ExporterNone keeps the signal's API working but exports nothing.
+	ExporterNone Exporter = "none"
+)
+
+// Compression selects how OTLP export requests are compressed.
+type Compression string
+
+const (
+	// CompressionNone sends export requests uncompressed (default).
+	CompressionNone Compression = "none"
+
+	// CompressionGzip compresses export requests with gzip.
+	CompressionGzip Compression = "gzip"
+)
+
+// Temporality selects whether exported metric points carry cumulative totals
+// or only the change since the previous export.
+type Temporality string
+
+const (
+	// TemporalityCumulative exports running totals for every instrument (default).
+	TemporalityCumulative Temporality = "cumulative"
+
+	// TemporalityDelta exports counters, observable counters and histograms
+	// as deltas. Up-down counters stay cumulative.
+	TemporalityDelta Temporality = "delta"
+
+	// TemporalityLowMemory exports synchronous counters and histograms as
+	// deltas and everything else as cumulative, which keeps the least state
+	// in the SDK.
+	TemporalityLowMemory Temporality = "lowmemory"
+)
+
+// selector returns the SDK temporality selector for t, following the
+// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE specification.
+func (t Temporality) selector() sdkmetric.TemporalitySelector {
+	switch t {
+	case TemporalityDelta:
+		return func(k sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch k {
+			case sdkmetric.InstrumentKindUpDownCounter, sdkmetric.InstrumentKindObservableUpDownCounter:
+				return metricdata.CumulativeTemporality
+			}
+			return metricdata.DeltaTemporality
+		}
+	case TemporalityLowMemory:
+		return func(k sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch k {
+			case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			}
+			return metricdata.CumulativeTemporality
+		}
+	}
+	return sdkmetric.DefaultTemporalitySelector
+}
+
+// RetryConfig configures how failed OTLP exports are retried with
+// exponential backoff. Zero values use the exporter defaults.
+type RetryConfig struct {
+	// Disabled turns retries off; failed batches are dropped.
+	Disabled bool
+
+	// InitialInterval is the wait after the first failure. Defaults to 5 seconds.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the wait between attempts. Defaults to 30 seconds.
+	MaxInterval time.Duration
+
+	// MaxElapsedTime is the total time spent retrying a batch before it is
+	// dropped. Defaults to 1 minute.
+	MaxElapsedTime time.Duration
+}
+
+func (r *RetryConfig) validate() error {
+	if r.InitialInterval < 0 || r.MaxInterval < 0 || r.MaxElapsedTime < 0 {
+		return fmt.Errorf("gintelemetry: Retry intervals cannot be negative")
+	}
+	if r.MaxInterval != 0 && r.MaxInterval < r.InitialInterval {
+		return fmt.Errorf("gintelemetry: Retry MaxInterval must not be less than InitialInterval")
+	}
+	if r.InitialInterval == 0 {
+		r.InitialInterval = 5 * time.Second
+		if r.MaxInterval != 0 {
+			r.InitialInterval = min(r.InitialInterval, r.MaxInterval)
+		}
+	}
+	if r.MaxInterval == 0 {
+		r.MaxInterval = max(30*time.Second, r.InitialInterval)
+	}
+	if r.MaxElapsedTime == 0 {
+		r.MaxElapsedTime = time.Minute
+	}
+	return nil
+}
+
+// SignalConfig holds settings for a single signal (traces, metrics or logs).
+// Zero values inherit the top-level Config settings.
+type SignalConfig struct {
+	// Disabled turns the signal off entirely. No provider is created and the
+	// signal's API calls become no-ops. Logs still go to the console.
+	Disabled bool
+
+	// Exporter overrides Config.Exporter for this signal.
+	// Defaults to OTEL_{TRACES,METRICS,LOGS}_EXPORTER.
+	Exporter Exporter
+
+	// Endpoint overrides Config.Endpoint for this signal.
+	// Defaults to OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_ENDPOINT.
+	Endpoint string
+
+	// Protocol overrides Config.Protocol for this signal.
+	// Defaults to OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_PROTOCOL.
+	Protocol Protocol
+
+	// Path is the URL path used by the OTLP/HTTP exporter, e.g.
+	// "/otlp/v1/traces". Defaults to the path of this signal's Endpoint,
+	// or the path of Config.Endpoint followed by "/v1/traces",
+	// "/v1/metrics" or "/v1/logs".
+	Path string
+
+	// Headers are sent with every export request for this signal, on top of
+	// Config.Headers. Defaults to OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_HEADERS.
+	Headers map[string]string
+
+	// Timeout overrides Config.Timeout for this signal.
+	// Defaults to OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_TIMEOUT.
+	Timeout time.Duration
+
+	// Compression overrides Config.Compression for this signal.
+	// Defaults to OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_COMPRESSION.
+	Compression Compression
+
+	// File overrides Config.ExportFile for this signal.
+	File string
+}
+
+// signalSettings are the effective exporter settings for one signal after
+// merging per-signal overrides with the top-level Config.
+type signalSettings struct {
+	disabled bool
+	exporter Exporter
+	endpoint string
+	scheme   string
+	path     string
+	protocol Protocol
+	insecure bool
+	tls      *tls.Config
+	headers  map[string]string
+	timeout  time.Duration
+	file     string
+	writer   io.Writer
+
+	compression Compression
+	retry       RetryConfig
+	credentials CredentialsProvider
+	temporality Temporality
+}
+
+// signal merges s with the top-level settings, where name is traces, metrics
+// or logs. Endpoints must already have been checked by validate.
+func (c *Config) signal(name string, s SignalConfig) signalSettings {
+	settings := signalSettings{
+		disabled: s.Disabled || c.Disabled,
+		exporter: c.Exporter,
+		endpoint: c.Endpoint,
+		protocol: c.Protocol,
+		insecure: c.Insecure,
+		headers:  mergeMaps(c.Headers, s.Headers),
+		timeout:  c.Timeout,
+		file:     c.ExportFile,
+		writer:   c.ExportWriter,
+
+		compression: c.Compression,
+		retry:       c.Retry,
+		credentials: c.Credentials,
+		temporality: c.MetricTemporality,
+	}
+	if s.Exporter != "" {
+		settings.exporter = s.Exporter
+	}
+	if s.Endpoint != "" {
+		settings.endpoint = s.Endpoint
+	}
+
+	// Derive the address, path, protocol and TLS from the endpoint URL. A
+	// top-level path is a prefix for the signal path, while a per-signal
+	// URL is used as-is, as in the OTLP exporter specification, so one
+	// without a path posts to the root rather than /v1/<signal>.
+	ep, _ := parseEndpoint(settings.endpoint)
+	settings.endpoint = ep.address
+	settings.scheme = ep.scheme
+	settings.insecure = ep.insecure(settings.insecure)
+	switch {
+	case s.Path != "":
+		settings.path = "/" + strings.TrimPrefix(s.Path, "/")
+	case s.Endpoint != "":
+		settings.path = ep.path
+		if settings.path == "" && (ep.scheme == "http" || ep.scheme == "https") {
+			settings.path = "/"
+		}
+	case ep.path != "":
+		settings.path = ep.path + "/v1/" + name
+	}
+	if s.Protocol != "" {
+		settings.protocol = s.Protocol
+	} else if p := ep.protocol(); p != "" && s.Endpoint != "" {
+		settings.protocol = p
+	}
+	if s.File != "" {
+		settings.file = s.File
+	}
+	if s.Timeout > 0 {
+		settings.timeout = s.Timeout
+	}
+	if s.Compression != "" {
+		settings.compression = s.Compression
+	}
+	if settings.writer == nil {
+		settings.writer = os.Stdout
+	}
+	return settings
+}
+
+// mergeMaps returns base overlaid with override, or nil if both are empty.
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// signalEnv fills unset fields of s from the OTEL_* variables of one signal,
+// where name is TRACES, METRICS or LOGS.
+func signalEnv(name string, s *SignalConfig) error {
+	if s.Exporter == "" {
+		if v := os.Getenv("OTEL_" + name + "_EXPORTER"); v != "" {
+			s.Exporter = Exporter(strings.ToLower(strings.TrimSpace(v)))
+		}
+	}
+	if s.Endpoint == "" {
+		s.Endpoint = os.Getenv("OTEL_EXPORTER_OTLP_" + name + "_ENDPOINT")
+	}
+	if s.Protocol == "" {
+		if v := os.Getenv("OTEL_EXPORTER_OTLP_" + name + "_PROTOCOL"); v != "" {
+			p, err := parseProtocol(v)
+			if err != nil {
+				return err
+			}
+			s.Protocol = p
+		}
+	}
+	if s.Headers == nil {
+		if v := os.Getenv("OTEL_EXPORTER_OTLP_" + name + "_HEADERS"); v != "" {
+			h, err := parseHeaders(v)
+			if err != nil {
+				return fmt.Errorf("gintelemetry: invalid OTEL_EXPORTER_OTLP_%s_HEADERS: %w", name, err)
+			}
+			s.Headers = h
+		}
+	}
+	if s.Timeout == 0 {
+		d, err := envMillis("OTEL_EXPORTER_OTLP_" + name + "_TIMEOUT")
+		if err != nil {
+			return err
+		}
+		s.Timeout = d
+	}
+	if s.Compression == "" {
+		s.Compression = Compression(os.Getenv("OTEL_EXPORTER_OTLP_" + name + "_COMPRESSION"))
+	}
+	if s.Compression != "" {
+		c, err := parseCompression(string(s.Compression))
+		if err != nil {
+			return err
+		}
+		s.Compression = c
+	}
+	return nil
+}
+
+// envMillis reads a duration in milliseconds, the unit used by the OTEL_*
+// timeout and interval variables. It returns 0 when key is unset.
+func envMillis(key string) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	ms, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("gintelemetry: invalid %s %q", key, v)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+func parseCompression(v string) (Compression, error) {
+	switch Compression(strings.ToLower(strings.TrimSpace(v))) {
+	case CompressionNone:
+		return CompressionNone, nil
+	case CompressionGzip:
+		return CompressionGzip, nil
+	}
+	return "", fmt.Errorf("gintelemetry: unsupported compression %q", v)
+}
+
+func parseTemporality(v string) (Temporality, error) {
+	switch Temporality(strings.ToLower(strings.TrimSpace(v))) {
+	case TemporalityCumulative:
+		return TemporalityCumulative, nil
+	case TemporalityDelta:
+		return TemporalityDelta, nil
+	case TemporalityLowMemory:
+		return TemporalityLowMemory, nil
+	}
+	return "", fmt.Errorf("gintelemetry: unsupported metric temporality %q", v)
+}
+
+// parseProtocol accepts both the short Protocol values and the
+// OTEL_EXPORTER_OTLP_PROTOCOL spelling such as "http/protobuf".
+func parseProtocol(v string) (Protocol, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "grpc":
+		return ProtocolGRPC, nil
+	case "http", "http/protobuf":
+		return ProtocolHTTP, nil
+	}
+	return "", fmt.Errorf("gintelemetry: unsupported protocol %q", v)
+}
+
+// parseHeaders parses the W3C-baggage-like "key1=value1,key2=value2" format
+// used by OTEL_EXPORTER_OTLP_HEADERS. Values may be URL-encoded.
+func parseHeaders(v string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("malformed header %q", pair)
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("malformed header value for %q: %w", key, err)
+		}
+		headers[key] = decoded
+	}
+	return headers, nil
+}
+
+func validateExporter(e Exporter) error {
+	switch e {
+	case ExporterOTLP, ExporterConsole, ExporterFile, ExporterNone:
+		return nil
+	}
+	return fmt.Errorf("gintelemetry: unknown exporter %q", e)
+}
+
+// writerFor returns the shared destination of a console or file exporter.
+func writerFor(s signalSettings, writers *exportWriters) (*lockedWriter, error) {
+	if s.exporter == ExporterFile {
+		return writers.get(s.file, nil)
+	}
+	return writers.get("", s.writer)
+}
+
+// newTraceExporter creates the span exporter for s. It returns nil when the
+// signal is not exported.
+func newTraceExporter(ctx context.Context, s signalSettings, writers *exportWriters) (sdktrace.SpanExporter, error) {
+	switch {
+	case s.disabled || s.exporter == ExporterNone:
+		return nil, nil
+	case s.exporter == ExporterConsole || s.exporter == ExporterFile:
+		out, err := writerFor(s, writers)
+		if err != nil {
+			return nil, err
+		}
+		opts := []stdouttrace.Option{stdouttrace.WithWriter(out)}
+		if s.exporter == ExporterConsole {
+			opts = append(opts, stdouttrace.WithPrettyPrint())
+		}
+		return stdouttrace.New(opts...)
+	}
+
+	if s.protocol == ProtocolHTTP {
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(s.endpoint),
+		}
+		if s.path != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(s.path))
+		}
+		if s.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else if s.tls != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(s.tls))
+		}
+		if len(s.headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(s.headers))
+		}
+		if s.timeout > 0 {
+			opts = append(opts, otlptracehttp.WithTimeout(s.timeout))
+		}
+		if s.compression == CompressionGzip {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		}
+		opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+			Enabled:         !s.retry.Disabled,
+			InitialInterval: s.retry.InitialInterval,
+			MaxInterval:     s.retry.MaxInterval,
+			MaxElapsedTime:  s.retry.MaxElapsedTime,
+		}))
+		if s.credentials != nil {
+			opts = append(opts, otlptracehttp.WithHTTPClient(newCredentialsClient(s)))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(s.endpoint),
+	}
+	if s.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else if s.tls != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(s.tls)))
+	}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(s.headers))
+	}
+	if s.timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(s.timeout))
+	}
+	if s.compression == CompressionGzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(string(CompressionGzip)))
+	}
+	opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{
+		Enabled:         !s.retry.Disabled,
+		InitialInterval: s.retry.InitialInterval,
+		MaxInterval:     s.retry.MaxInterval,
+		MaxElapsedTime:  s.retry.MaxElapsedTime,
+	}))
+	if s.credentials != nil {
+		opts = append(opts, otlptracegrpc.WithDialOption(grpc.WithPerRPCCredentials(
+			grpcCredentials{provider: s.credentials, requireTLS: !s.insecure})))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newMetricExporter creates the metric exporter for s. It returns nil when
+// the signal is not exported.
+func newMetricExporter(ctx context.Context, s signalSettings, writers *exportWriters) (sdkmetric.Exporter, error) {
+	switch {
+	case s.disabled || s.exporter == ExporterNone:
+		return nil, nil
+	case s.exporter == ExporterConsole || s.exporter == ExporterFile:
+		out, err := writerFor(s, writers)
+		if err != nil {
+			return nil, err
+		}
+		opts := []stdoutmetric.Option{
+			stdoutmetric.WithWriter(out),
+			stdoutmetric.WithTemporalitySelector(s.temporality.selector()),
+		}
+		if s.exporter == ExporterConsole {
+			opts = append(opts, stdoutmetric.WithPrettyPrint())
+		}
+		return stdoutmetric.New(opts...)
+	}
+
+	if s.protocol == ProtocolHTTP {
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(s.endpoint),
+			otlpmetrichttp.WithTemporalitySelector(s.temporality.selector()),
+		}
+		if s.path != "" {
+			opts = append(opts, otlpmetrichttp.WithURLPath(s.path))
+		}
+		if s.insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		} else if s.tls != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(s.tls))
+		}
+		if len(s.headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(s.headers))
+		}
+		if s.timeout > 0 {
+			opts = append(opts, otlpmetrichttp.WithTimeout(s.timeout))
+		}
+		if s.compression == CompressionGzip {
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		}
+		opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+			Enabled:         !s.retry.Disabled,
+			InitialInterval: s.retry.InitialInterval,
+			MaxInterval:     s.retry.MaxInterval,
+			MaxElapsedTime:  s.retry.MaxElapsedTime,
+		}))
+		if s.credentials != nil {
+			opts = append(opts, otlpmetrichttp.WithHTTPClient(newCredentialsClient(s)))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(s.endpoint),
+		otlpmetricgrpc.WithTemporalitySelector(s.temporality.selector()),
+	}
+	if s.insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else if s.tls != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(s.tls)))
+	}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(s.headers))
+	}
+	if s.timeout > 0 {
+		opts = append(opts, otlpmetricgrpc.WithTimeout(s.timeout))
+	}
+	if s.compression == CompressionGzip {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(string(CompressionGzip)))
+	}
+	opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{
+		Enabled:         !s.retry.Disabled,
+		InitialInterval: s.retry.InitialInterval,
+		MaxInterval:     s.retry.MaxInterval,
+		MaxElapsedTime:  s.retry.MaxElapsedTime,
+	}))
+	if s.credentials != nil {
+		opts = append(opts, otlpmetricgrpc.WithDialOption(grpc.WithPerRPCCredentials(
+			grpcCredentials{provider: s.credentials, requireTLS: !s.insecure})))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// newLogExporter creates the log exporter for s. It returns nil when the
+// signal is not exported.
+func newLogExporter(ctx context.Context, s signalSettings, writers *exportWriters) (sdklog.Exporter, error) {
+	switch {
+	case s.disabled || s.exporter == ExporterNone:
+		return nil, nil
+	case s.exporter == ExporterConsole || s.exporter == ExporterFile:
+		out, err := writerFor(s, writers)
+		if err != nil {
+			return nil, err
+		}
+		opts := []stdoutlog.Option{stdoutlog.WithWriter(out)}
+		if s.exporter == ExporterConsole {
+			opts = append(opts, stdoutlog.WithPrettyPrint())
+		}
+		return stdoutlog.New(opts...)
+	}
+
+	if s.protocol == ProtocolHTTP {
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(s.endpoint),
+		}
+		if s.path != "" {
+			opts = append(opts, otlploghttp.WithURLPath(s.path))
+		}
+		if s.insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		} else if s.tls != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(s.tls))
+		}
+		if len(s.headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(s.headers))
+		}
+		if s.timeout > 0 {
+			opts = append(opts, otlploghttp.WithTimeout(s.timeout))
+		}
+		if s.compression == CompressionGzip {
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		}
+		opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig{
+			Enabled:         !s.retry.Disabled,
+			InitialInterval: s.retry.InitialInterval,
+			MaxInterval:     s.retry.MaxInterval,
+			MaxElapsedTime:  s.retry.MaxElapsedTime,
+		}))
+		if s.credentials != nil {
+			opts = append(opts, otlploghttp.WithHTTPClient(newCredentialsClient(s)))
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(s.endpoint),
+	}
+	if s.insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	} else if s.tls != nil {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(s.tls)))
+	}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(s.headers))
+	}
+	if s.timeout > 0 {
+		opts = append(opts, otlploggrpc.WithTimeout(s.timeout))
+	}
+	if s.compression == CompressionGzip {
+		opts = append(opts, otlploggrpc.WithCompressor(string(CompressionGzip)))
+	}
+	opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig{
+		Enabled:         !s.retry.Disabled,
+		InitialInterval: s.retry.InitialInterval,
+		MaxInterval:     s.retry.MaxInterval,
+		MaxElapsedTime:  s.retry.MaxElapsedTime,
+	}))
+	if s.credentials != nil {
+		opts = append(opts, otlploggrpc.WithDialOption(grpc.WithPerRPCCredentials(
+			grpcCredentials{provider: s.credentials, requireTLS: !s.insecure})))
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
@@ -0,0 +1,97 @@
+package gintelemetry
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FilterConfig excludes requests, such as health checks and static assets,
+// from tracing and HTTP server metrics. A request is excluded when any of
+// the fields match it.
+//
+// Example:
+//
+//	Filter: gintelemetry.FilterConfig{
+//	    Routes:   []string{"/health", "/ready", "/static/*"},
+//	    Patterns: []string{`^/debug/`},
+//	}
+type FilterConfig struct {
+	// Routes are matched against the Gin route template, e.g. "/users/:id",
+	// and the request path. They may contain path.Match wildcards, where "*"
+	// matches within one path segment.
+	Routes []string
+
+	// Patterns are regular expressions matched against the request path.
+	Patterns []string
+
+	// Request excludes requests for which it returns true.
+	Request func(r *http.Request) bool
+
+	// Context excludes requests for which it returns true. Unlike Request
+	// it can see the matched route and values set by earlier middleware.
+	Context func(c *gin.Context) bool
+}
+
+func (f *FilterConfig) validate() error {
+	for _, route := range f.Routes {
+		if _, err := path.Match(route, ""); err != nil {
+			return fmt.Errorf("gintelemetry: invalid filter route %q: %w", route, err)
+		}
+	}
+	for _, pattern := range f.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("gintelemetry: invalid filter pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// requestFilter is a compiled FilterConfig.
+type requestFilter struct {
+	routes   []string
+	patterns []*regexp.Regexp
+	request  func(*http.Request) bool
+	context  func(*gin.Context) bool
+}
+
+// newRequestFilter compiles f, which must have been validated, with extra
+// routes to exclude. It returns nil when nothing is filtered.
+func newRequestFilter(f FilterConfig, routes ...string) *requestFilter {
+	routes = append(routes, f.Routes...)
+	if len(routes) == 0 && len(f.Patterns) == 0 && f.Request == nil && f.Context == nil {
+		return nil
+	}
+	rf := &requestFilter{routes: routes, request: f.Request, context: f.Context}
+	for _, pattern := range f.Patterns {
+		rf.patterns = append(rf.patterns, regexp.MustCompile(pattern))
+	}
+	return rf
+}
+
+// match reports whether the request should be excluded from telemetry.
+func (f *requestFilter) match(c *gin.Context) bool {
+	if f == nil {
+		return false
+	}
+	route, p := c.FullPath(), c.Request.URL.Path
+	for _, r := range f.routes {
+		if ok, _ := path.Match(r, p); ok {
+			return true
+		}
+		if route != "" {
+			if ok, _ := path.Match(r, route); ok {
+				return true
+			}
+		}
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return (f.request != nil && f.request(c.Request)) || (f.context != nil && f.context(c))
+}
@@ -0,0 +1,140 @@
+package gintelemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestFilter_Match(t *testing.T) {
+	cfg := FilterConfig{
+		Routes:   []string{"/health", "/static/*", "/users/:id"},
+		Patterns: []string{`^/debug/`},
+		Request:  func(r *http.Request) bool { return strings.HasPrefix(r.UserAgent(), "kube-probe/") },
+		Context:  func(c *gin.Context) bool { return c.GetBool("skip") },
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	f := newRequestFilter(cfg, "/metrics")
+
+	tests := []struct {
+		name      string
+		path      string
+		userAgent string
+		skip      bool
+		want      bool
+	}{
+		{name: "route", path: "/health", want: true},
+		{name: "extra route", path: "/metrics", want: true},
+		{name: "glob", path: "/static/app.js", want: true},
+		{name: "glob within one segment", path: "/static/js/app.js"},
+		{name: "route template", path: "/users/42", want: true},
+		{name: "pattern", path: "/debug/pprof", want: true},
+		{name: "request func", path: "/orders", userAgent: "kube-probe/1.29", want: true},
+		{name: "context func", path: "/orders", skip: true, want: true},
+		{name: "not filtered", path: "/orders"},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			router := gin.New()
+			handler := func(c *gin.Context) {
+				c.Set("skip", tt.skip)
+				got = f.match(c)
+			}
+			router.GET("/users/:id", handler)
+			router.NoRoute(handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("User-Agent", tt.userAgent)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("match(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if newRequestFilter(FilterConfig{}) != nil {
+		t.Error("newRequestFilter() of an empty config is not nil")
+	}
+}
+
+// Filtered requests get neither a span nor server metrics, and the rest of
+// the chain still runs.
+func TestMiddleware_Filter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	cfg := NewTestConfig("test")
+	cfg.Exporter = ExporterNone
+	cfg.SpanProcessors = []sdktrace.SpanProcessor{recorder}
+	cfg.Filter = FilterConfig{Routes: []string{"/health"}}
+	tel, router, err := Start(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = tel.Shutdown(context.Background()) })
+
+	var served []string
+	for _, route := range []string{"/health", "/orders"} {
+		router.GET(route, func(c *gin.Context) { served = append(served, c.FullPath()) })
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, route, nil))
+	}
+
+	if len(served) != 2 {
+		t.Errorf("served = %v, want both routes", served)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if v, _ := spanAttr(spans[0], "http.route"); v.AsString() != "/orders" {
+		t.Errorf("span route = %q, want /orders", v.AsString())
+	}
+}
+
+func TestServerMetrics_Filter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	metrics, err := newServerMetrics(provider.Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := newRequestFilter(FilterConfig{Routes: []string{"/health"}})
+
+	router := gin.New()
+	router.Use(metrics.middleware(func(c *gin.Context) { c.Next() }, filter))
+	for _, route := range []string{"/health", "/orders"} {
+		router.GET(route, func(c *gin.Context) {})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, route, nil))
+	}
+
+	rm := collect(t, reader)
+	var routes []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.request.duration" {
+				continue
+			}
+			for _, p := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+				route, _ := p.Attributes.Value("http.route")
+				routes = append(routes, route.AsString())
+			}
+		}
+	}
+	if len(routes) != 1 || routes[0] != "/orders" {
+		t.Errorf("recorded routes = %v, want [/orders]", routes)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"sync"
 	"time"
 
@@ -13,22 +14,23 @@ import (
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	lognoop "go.opentelemetry.io/otel/log/noop"
 	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
-	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
+// instrumentationName is the scope name used for telemetry produced by this
+// package itself, such as tail sampling counters.
+const instrumentationName = "github.com/Levy-Tal/gintelemetry"
+
 type Telemetry struct {
 	serviceName     string
 	tracerProvider  *sdktrace.TracerProvider
@@ -37,132 +39,280 @@ type Telemetry struct {
 	logger          *slog.Logger
 	meter           metric.Meter
 	tracer          trace.Tracer
+	propagator      propagation.TextMapPropagator
+	exportWriters   *exportWriters
+	settings        []Setting
+	middleware      gin.HandlerFunc
+	metricsHandler  http.Handler
+	metricsRoute    string
+	filter          *requestFilter
+	metricsServer   *http.Server
 	shutdownTimeout time.Duration
 	shutdownOnce    sync.Once
 	shutdownErr     error
 	shutdownDone    chan struct{}
 }
 
+// Start initializes telemetry and returns a new Gin router with
+// gin.Recovery and the telemetry middleware installed. Use New and
+// Instrument instead to add telemetry to an existing engine.
 func Start(ctx context.Context, cfg Config) (*Telemetry, *gin.Engine, error) {
-	if err := cfg.validate(); err != nil {
+	t, err := New(ctx, cfg)
+	if err != nil {
 		return nil, nil, err
 	}
 
-	// Create resource with service name and global attributes
-	attrs := []attribute.KeyValue{
-		attribute.String("service.name", cfg.ServiceName),
+	router := gin.New()
+	router.Use(gin.Recovery())
+	t.Instrument(router)
+
+	return t, router, nil
+}
+
+// New initializes telemetry without creating a router. Add the middleware
+// to your own engine with Instrument or Middleware.
+func New(ctx context.Context, cfg Config) (*Telemetry, error) {
+	base := cfg
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
-	for k, v := range cfg.GlobalAttributes {
-		attrs = append(attrs, attribute.String(k, v))
+
+	propagator, err := newPropagator(cfg.Propagators)
+	if err != nil {
+		return nil, err
 	}
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes("", attrs...),
-	)
+
+	res, err := newResource(ctx, cfg)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create resource: %w", err)
+		return nil, err
 	}
 
-	// Create exporters based on protocol
-	var traceExporter sdktrace.SpanExporter
-	var metricExporter sdkmetric.Exporter
-	var logExporter sdklog.Exporter
+	// With DegradeOnError, a signal that cannot be exported is turned off
+	// and the reason is logged once the logger exists.
+	type degradedSignal struct {
+		name string
+		err  error
+	}
+	var degraded []degradedSignal
+	degrade := func(name string, s *signalSettings, err error) {
+		s.disabled = true
+		degraded = append(degraded, degradedSignal{name: name, err: err})
+	}
 
-	if cfg.Protocol == ProtocolHTTP {
-		// HTTP exporters
-		traceOpts := []otlptracehttp.Option{
-			otlptracehttp.WithEndpoint(cfg.Endpoint),
+	// Create exporters for each signal
+	traceSettings := cfg.signal("traces", cfg.Traces)
+	metricSettings := cfg.signal("metrics", cfg.Metrics)
+	logSettings := cfg.signal("logs", cfg.Logs)
+	signals := []struct {
+		name     string
+		settings *signalSettings
+	}{
+		{"traces", &traceSettings},
+		{"metrics", &metricSettings},
+		{"logs", &logSettings},
+	}
+
+	// Certificates are only loaded when a signal is exported over OTLP
+	var certs *certReloader
+	var tlsErr error
+	for _, sg := range signals {
+		if !sg.settings.disabled && sg.settings.exporter == ExporterOTLP {
+			certs, tlsErr = newCertReloader(cfg.TLS)
+			break
 		}
-		if cfg.Insecure {
-			traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+	}
+	if tlsErr != nil && !cfg.DegradeOnError {
+		return nil, tlsErr
+	}
+
+	for _, sg := range signals {
+		s := sg.settings
+		s.tls = certs.tlsConfig(s.endpoint)
+		if s.disabled || s.exporter != ExporterOTLP {
+			continue
 		}
-		traceExporter, err = otlptracehttp.New(ctx, traceOpts...)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		switch {
+		case s.endpoint == "":
+			degrade(sg.name, s, errors.New("no OTLP endpoint configured"))
+		case tlsErr != nil && !s.insecure:
+			degrade(sg.name, s, tlsErr)
 		}
+	}
 
-		metricOpts := []otlpmetrichttp.Option{
-			otlpmetrichttp.WithEndpoint(cfg.Endpoint),
-		}
-		if cfg.Insecure {
-			metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+	writers := &exportWriters{}
+	traceExporter, err := newTraceExporter(ctx, traceSettings, writers)
+	if err != nil {
+		if !cfg.DegradeOnError {
+			_ = writers.Close()
+			return nil, fmt.Errorf("failed to create trace exporter: %w", err)
 		}
-		metricExporter, err = otlpmetrichttp.New(ctx, metricOpts...)
-		if err != nil {
-			_ = traceExporter.Shutdown(ctx)
-			return nil, nil, fmt.Errorf("failed to create metric exporter: %w", err)
+		degrade("traces", &traceSettings, err)
+	}
+
+	metricExporter, err := newMetricExporter(ctx, metricSettings, writers)
+	if err != nil {
+		if !cfg.DegradeOnError {
+			if traceExporter != nil {
+				_ = traceExporter.Shutdown(ctx)
+			}
+			_ = writers.Close()
+			return nil, fmt.Errorf("failed to create metric exporter: %w", err)
 		}
+		degrade("metrics", &metricSettings, err)
+	}
 
-		logOpts := []otlploghttp.Option{
-			otlploghttp.WithEndpoint(cfg.Endpoint),
+	logExporter, err := newLogExporter(ctx, logSettings, writers)
+	if err != nil {
+		if !cfg.DegradeOnError {
+			if traceExporter != nil {
+				_ = traceExporter.Shutdown(ctx)
+			}
+			if metricExporter != nil {
+				_ = metricExporter.Shutdown(ctx)
+			}
+			_ = writers.Close()
+			return nil, fmt.Errorf("failed to create log exporter: %w", err)
 		}
-		if cfg.Insecure {
-			logOpts = append(logOpts, otlploghttp.WithInsecure())
+		degrade("logs", &logSettings, err)
+	}
+
+	redact := newRedactor(cfg.Redaction)
+
+	// Create providers. Disabled signals get no provider and use no-op
+	// implementations of the API instead.
+	var tp trace.TracerProvider = tracenoop.NewTracerProvider()
+	var tracerProvider *sdktrace.TracerProvider
+	var tailSampler *tailSamplingProcessor
+	if !traceSettings.disabled {
+		tracerOpts := []sdktrace.TracerProviderOption{
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(newSampler(cfg.Sampling)),
+			sdktrace.WithRawSpanLimits(cfg.Limits.spanLimits()),
 		}
-		logExporter, err = otlploghttp.New(ctx, logOpts...)
-		if err != nil {
-			_ = traceExporter.Shutdown(ctx)
-			_ = metricExporter.Shutdown(ctx)
-			return nil, nil, fmt.Errorf("failed to create log exporter: %w", err)
+		if traceExporter != nil {
+			processor := cfg.TraceBatch.spanProcessor(traceExporter)
+			if cfg.TailSampling.Enabled {
+				tailSampler = newTailSamplingProcessor(cfg.TailSampling, processor)
+				processor = tailSampler
+			}
+			if redact != nil {
+				processor = &redactingProcessor{next: processor, redactor: redact}
+			}
+			tracerOpts = append(tracerOpts, sdktrace.WithSpanProcessor(processor))
 		}
-	} else {
-		// gRPC exporters (default)
-		traceOpts := []otlptracegrpc.Option{
-			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		for _, p := range cfg.SpanProcessors {
+			if redact != nil {
+				p = &redactingProcessor{next: p, redactor: redact}
+			}
+			tracerOpts = append(tracerOpts, sdktrace.WithSpanProcessor(p))
 		}
-		if cfg.Insecure {
-			traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+		tracerProvider = sdktrace.NewTracerProvider(tracerOpts...)
+		tp = tracerProvider
+	}
+
+	var mp metric.MeterProvider = metricnoop.NewMeterProvider()
+	var meterProvider *sdkmetric.MeterProvider
+	var metricsHandler http.Handler
+	var limits *cardinalityLimits
+	if !metricSettings.disabled {
+		limits = newCardinalityLimits(cfg.Cardinality, redact, cfg.Views)
+		meterOpts := []sdkmetric.Option{
+			sdkmetric.WithResource(res),
+			sdkmetric.WithCardinalityLimit(cfg.Cardinality.sdkLimit()),
 		}
-		traceExporter, err = otlptracegrpc.New(ctx, traceOpts...)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		if len(cfg.Views) > 0 {
+			meterOpts = append(meterOpts, sdkmetric.WithView(newView(cfg.Views)))
 		}
-
-		metricOpts := []otlpmetricgrpc.Option{
-			otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
+		if metricExporter != nil {
+			meterOpts = append(meterOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
+				resettingExporter{Exporter: metricExporter, limits: limits},
+				sdkmetric.WithInterval(cfg.MetricInterval),
+				sdkmetric.WithTimeout(cfg.MetricTimeout))))
 		}
-		if cfg.Insecure {
-			metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+		if cfg.Prometheus.Enabled {
+			var reader sdkmetric.Reader
+			reader, metricsHandler, err = newPrometheus()
+			if err != nil {
+				if tracerProvider != nil {
+					_ = tracerProvider.Shutdown(ctx)
+				}
+				if metricExporter != nil {
+					_ = metricExporter.Shutdown(ctx)
+				}
+				if logExporter != nil {
+					_ = logExporter.Shutdown(ctx)
+				}
+				_ = writers.Close()
+				return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+			}
+			meterOpts = append(meterOpts, sdkmetric.WithReader(reader))
 		}
-		metricExporter, err = otlpmetricgrpc.New(ctx, metricOpts...)
-		if err != nil {
-			_ = traceExporter.Shutdown(ctx)
-			return nil, nil, fmt.Errorf("failed to create metric exporter: %w", err)
+		meterProvider = sdkmetric.NewMeterProvider(meterOpts...)
+		mp = meterProvider
+	}
+
+	// Wrap the meter provider to redact attributes, enforce per-instrument
+	// cardinality limits and report overflows.
+	if meterProvider != nil {
+		if err := limits.registerMetrics(meterProvider.Meter(instrumentationName)); err != nil {
+			_ = meterProvider.Shutdown(ctx)
+			if tracerProvider != nil {
+				_ = tracerProvider.Shutdown(ctx)
+			}
+			if logExporter != nil {
+				_ = logExporter.Shutdown(ctx)
+			}
+			_ = writers.Close()
+			return nil, fmt.Errorf("failed to register cardinality metrics: %w", err)
 		}
+		mp = limitedMeterProvider{MeterProvider: meterProvider, limits: limits}
+	}
 
-		logOpts := []otlploggrpc.Option{
-			otlploggrpc.WithEndpoint(cfg.Endpoint),
+	if tailSampler != nil {
+		if err := tailSampler.registerMetrics(mp.Meter(instrumentationName)); err != nil {
+			_ = tracerProvider.Shutdown(ctx)
+			if meterProvider != nil {
+				_ = meterProvider.Shutdown(ctx)
+			}
+			if logExporter != nil {
+				_ = logExporter.Shutdown(ctx)
+			}
+			_ = writers.Close()
+			return nil, fmt.Errorf("failed to register tail sampling metrics: %w", err)
 		}
-		if cfg.Insecure {
-			logOpts = append(logOpts, otlploggrpc.WithInsecure())
+	}
+
+	var lp otellog.LoggerProvider = lognoop.NewLoggerProvider()
+	var loggerProvider *sdklog.LoggerProvider
+	if !logSettings.disabled {
+		loggerOpts := []sdklog.LoggerProviderOption{
+			sdklog.WithResource(res),
+			sdklog.WithAttributeCountLimit(cfg.Limits.AttributeCount),
+			sdklog.WithAttributeValueLengthLimit(cfg.Limits.AttributeValueLength),
 		}
-		logExporter, err = otlploggrpc.New(ctx, logOpts...)
-		if err != nil {
-			_ = traceExporter.Shutdown(ctx)
-			_ = metricExporter.Shutdown(ctx)
-			return nil, nil, fmt.Errorf("failed to create log exporter: %w", err)
+		if logExporter != nil {
+			loggerOpts = append(loggerOpts, sdklog.WithProcessor(cfg.LogBatch.logProcessor(logExporter)))
 		}
+		loggerProvider = sdklog.NewLoggerProvider(loggerOpts...)
+		lp = loggerProvider
 	}
 
-	// Create providers
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(traceExporter),
-		sdktrace.WithResource(res),
-	)
-
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
-		sdkmetric.WithResource(res),
-	)
-
-	loggerProvider := sdklog.NewLoggerProvider(
-		sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter)),
-		sdklog.WithResource(res),
-	)
-
-	// Create logger with dual output (OTLP + stdout)
-	logger := otelslog.NewLogger(cfg.ServiceName, otelslog.WithLoggerProvider(loggerProvider))
-	logger = applyLevelFilter(logger, cfg.getLogLevel())
+	// Create logger with dual output (OTLP + console), or console only when
+	// logs are disabled
+	var otelLogger *slog.Logger
+	if loggerProvider != nil {
+		otelLogger = otelslog.NewLogger(cfg.ServiceName, otelslog.WithLoggerProvider(lp))
+	}
+	logger := applyLevelFilter(otelLogger, cfg.getLogLevel(), cfg.Console)
+	if redact != nil {
+		logger = slog.New(&redactingHandler{next: logger.Handler(), redactor: redact})
+	}
+	if limits != nil {
+		limits.logger = logger
+	}
+	for _, d := range degraded {
+		logger.Warn("gintelemetry: signal disabled", "signal", d.name, "error", d.err)
+	}
 
 	t := &Telemetry{
 		serviceName:     cfg.ServiceName,
@@ -170,25 +320,114 @@ func Start(ctx context.Context, cfg Config) (*Telemetry, *gin.Engine, error) {
 		meterProvider:   meterProvider,
 		loggerProvider:  loggerProvider,
 		logger:          logger,
-		meter:           meterProvider.Meter(cfg.ServiceName),
-		tracer:          tracerProvider.Tracer(cfg.ServiceName),
+		meter:           mp.Meter(cfg.ServiceName),
+		tracer:          tp.Tracer(cfg.ServiceName),
+		propagator:      propagator,
+		exportWriters:   writers,
+		settings:        cfg.settings(&base),
+		metricsHandler:  metricsHandler,
 		shutdownTimeout: cfg.getShutdownTimeout(),
 		shutdownDone:    make(chan struct{}),
 	}
 
 	// Set global providers if requested
 	if cfg.SetGlobalProvider {
-		otel.SetTracerProvider(tracerProvider)
-		otel.SetMeterProvider(meterProvider)
-		global.SetLoggerProvider(loggerProvider)
+		otel.SetTracerProvider(tp)
+		otel.SetMeterProvider(mp)
+		global.SetLoggerProvider(lp)
+		otel.SetTextMapPropagator(propagator)
 	}
 
-	// Create Gin router with recovery and tracing middleware
-	router := gin.New()
-	router.Use(gin.Recovery(), otelgin.Middleware(cfg.ServiceName,
-		otelgin.WithTracerProvider(tracerProvider)))
+	// Serve Prometheus metrics on their own listener, or leave the route
+	// for Instrument to mount on the router.
+	if metricsHandler != nil {
+		if cfg.Prometheus.Addr != "" {
+			t.metricsServer, err = startMetricsServer(cfg.Prometheus.Addr, cfg.Prometheus.Path, metricsHandler)
+			if err != nil {
+				_ = t.Shutdown(ctx)
+				return nil, fmt.Errorf("failed to start metrics server: %w", err)
+			}
+		} else {
+			t.metricsRoute = cfg.Prometheus.Path
+		}
+	}
 
-	return t, router, nil
+	if t.Enabled() {
+		headers := newHeaderCapture(cfg.CaptureHeaders)
+		metrics, err := newServerMetrics(mp.Meter(instrumentationName))
+		if err != nil {
+			_ = t.Shutdown(ctx)
+			return nil, fmt.Errorf("failed to create server metrics: %w", err)
+		}
+		// Scrapes are not worth a span or metrics each, so the metrics
+		// route is always filtered.
+		var routes []string
+		if t.metricsRoute != "" {
+			routes = append(routes, t.metricsRoute)
+		}
+		t.filter = newRequestFilter(cfg.Filter, routes...)
+
+		tracingOpts := []otelgin.Option{
+			otelgin.WithTracerProvider(serverTracerProvider{tp}),
+			otelgin.WithPropagators(propagator),
+			// Server metrics are recorded by serverMetrics, so keep otelgin
+			// from recording its own into the global meter provider.
+			otelgin.WithMeterProvider(metricnoop.NewMeterProvider()),
+		}
+		if t.filter != nil {
+			tracingOpts = append(tracingOpts, otelgin.WithGinFilter(func(c *gin.Context) bool {
+				return !t.filter.match(c)
+			}))
+		}
+		tracing := otelgin.Middleware(cfg.ServiceName, tracingOpts...)
+		t.middleware = serverRequests(headers, metrics.middleware(tracing, t.filter))
+	} else {
+		t.middleware = func(c *gin.Context) { c.Next() }
+	}
+
+	return t, nil
+}
+
+// Enabled reports whether any signal is active. It is false when telemetry
+// is disabled, in which case all API calls are no-ops and logs only go to
+// the console.
+func (t *Telemetry) Enabled() bool {
+	return t != nil && (t.tracerProvider != nil || t.meterProvider != nil || t.loggerProvider != nil)
+}
+
+// Middleware returns the Gin middleware that traces requests and records
+// HTTP server metrics. Register it early, after any recovery handler, so
+// that spans and durations cover the whole chain.
+func (t *Telemetry) Middleware() gin.HandlerFunc {
+	if t == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return t.middleware
+}
+
+// Instrument adds the telemetry middleware to an existing engine and, when
+// Prometheus is enabled without its own Addr, the metrics route.
+func (t *Telemetry) Instrument(engine *gin.Engine) {
+	engine.Use(t.Middleware())
+	if t != nil && t.metricsRoute != "" {
+		engine.GET(t.metricsRoute, gin.WrapH(t.metricsHandler))
+	}
+}
+
+// Filtered reports whether the request is excluded from telemetry by
+// Config.Filter, so that access-log middleware can skip the same requests.
+func (t *Telemetry) Filtered(c *gin.Context) bool {
+	return t != nil && t.filter.match(c)
+}
+
+// MetricsHandler returns the handler serving Prometheus metrics, or nil
+// when Config.Prometheus is not enabled. Use it to mount the endpoint on a
+// router other than the one passed to Instrument.
+func (t *Telemetry) MetricsHandler() http.Handler {
+	if t == nil {
+		return nil
+	}
+	return t.metricsHandler
 }
 
 func (t *Telemetry) Flush(ctx context.Context) error {
@@ -232,6 +471,11 @@ func (t *Telemetry) Shutdown(ctx context.Context) error {
 		}
 
 		var errs []error
+		if t.metricsServer != nil {
+			if err := t.metricsServer.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("metrics server shutdown: %w", err))
+			}
+		}
 		if t.tracerProvider != nil {
 			if err := t.tracerProvider.Shutdown(ctx); err != nil {
 				errs = append(errs, fmt.Errorf("tracer shutdown: %w", err))
@@ -247,6 +491,11 @@ func (t *Telemetry) Shutdown(ctx context.Context) error {
 				errs = append(errs, fmt.Errorf("logger shutdown: %w", err))
 			}
 		}
+		if t.exportWriters != nil {
+			if err := t.exportWriters.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("export file close: %w", err))
+			}
+		}
 
 		t.shutdownErr = errors.Join(errs...)
 	})
@@ -268,7 +517,7 @@ func (t *Telemetry) Log() LogAPI {
 }
 
 func (t *Telemetry) Trace() TraceAPI {
-	return TraceAPI{tracer: t.tracer}
+	return TraceAPI{tracer: t.tracer, propagator: t.propagator}
 }
 
 func (t *Telemetry) Metric() MetricAPI {
@@ -287,6 +536,13 @@ func (t *Telemetry) TracerProvider() *sdktrace.TracerProvider {
 	return t.tracerProvider
 }
 
+// MeterProvider returns the underlying SDK meter provider, for flushing and
+// for libraries that require an *sdkmetric.MeterProvider.
+//
+// WARNING: instruments created from it bypass Redaction and the
+// per-instrument Cardinality limits, so their attributes are exported as
+// recorded. Use Metric(), or the global meter provider with
+// SetGlobalProvider, for instruments whose attributes may carry personal data.
 func (t *Telemetry) MeterProvider() *sdkmetric.MeterProvider {
 	if t == nil {
 		return nil
@@ -294,6 +550,30 @@ func (t *Telemetry) MeterProvider() *sdkmetric.MeterProvider {
 	return t.meterProvider
 }
 
+// Propagator returns the configured context propagator. Use it to inject
+// trace headers into outbound requests in the same formats that incoming
+// requests are read with.
+func (t *Telemetry) Propagator() propagation.TextMapPropagator {
+	if t == nil {
+		return nil
+	}
+	return t.propagator
+}
+
+// Settings reports the effective configuration and whether each value came
+// from Config, an environment variable or a default. Useful for logging at
+// startup to see which OTEL_* variables took effect.
+func (t *Telemetry) Settings() []Setting {
+	if t == nil {
+		return nil
+	}
+	return append([]Setting(nil), t.settings...)
+}
+
+// LoggerProvider returns the underlying SDK logger provider.
+//
+// WARNING: records emitted through it bypass Redaction. Use Log() for
+// records that may carry personal data.
 func (t *Telemetry) LoggerProvider() *sdklog.LoggerProvider {
 	if t == nil {
 		return nil
@@ -0,0 +1,144 @@
+package gintelemetry
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderConfig selects HTTP headers to record on server spans as
+// http.request.header.<name> and http.response.header.<name> attributes.
+// Names are matched case-insensitively.
+//
+// Credentials are never recorded: the values of Authorization,
+// Proxy-Authorization, Cookie, Set-Cookie, X-Api-Key and X-Auth-Token are
+// replaced with "[REDACTED]" even when listed.
+//
+// Example:
+//
+//	CaptureHeaders: gintelemetry.HeaderConfig{
+//	    Request:  []string{"X-Request-ID", "User-Agent", "X-Client-Version"},
+//	    Response: []string{"Content-Type"},
+//	}
+type HeaderConfig struct {
+	// Request lists request headers to record. Defaults to
+	// OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_REQUEST.
+	Request []string
+
+	// Response lists response headers to record. Defaults to
+	// OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_RESPONSE.
+	Response []string
+
+	// MaxLength truncates longer header values, in bytes. Negative means
+	// unlimited. Defaults to 256.
+	MaxLength int
+}
+
+func (h *HeaderConfig) validate() {
+	if h.Request == nil {
+		h.Request = envList("OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_REQUEST")
+	}
+	if h.Response == nil {
+		h.Response = envList("OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_RESPONSE")
+	}
+	if h.MaxLength == 0 {
+		h.MaxLength = 256
+	}
+}
+
+// sensitiveHeaders are redacted regardless of HeaderConfig, keyed by
+// canonical name.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Auth-Token":        true,
+}
+
+// headerCapture records the configured headers on the request span.
+type headerCapture struct {
+	request   []string
+	response  []string
+	maxLength int
+}
+
+// newHeaderCapture returns nil when no headers are captured.
+func newHeaderCapture(cfg HeaderConfig) *headerCapture {
+	if len(cfg.Request) == 0 && len(cfg.Response) == 0 {
+		return nil
+	}
+	return &headerCapture{
+		request:   canonicalHeaders(cfg.Request),
+		response:  canonicalHeaders(cfg.Response),
+		maxLength: cfg.MaxLength,
+	}
+}
+
+// record adds the headers to span, the request's server span. It runs
+// after the handlers so that response headers are known.
+func (h *headerCapture) record(c *gin.Context, span trace.Span) {
+	if h == nil {
+		return
+	}
+	if !span.IsRecording() {
+		return
+	}
+	attrs := h.attributes("http.request.header.", h.request, c.Request.Header)
+	attrs = append(attrs, h.attributes("http.response.header.", h.response, c.Writer.Header())...)
+	span.SetAttributes(attrs...)
+}
+
+func (h *headerCapture) attributes(prefix string, names []string, header http.Header) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		recorded := make([]string, len(values))
+		for i, v := range values {
+			if sensitiveHeaders[name] {
+				v = "[REDACTED]"
+			} else if h.maxLength >= 0 && len(v) > h.maxLength {
+				v = truncateUTF8(v, h.maxLength)
+			}
+			recorded[i] = v
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+strings.ToLower(name), recorded))
+	}
+	return attrs
+}
+
+func canonicalHeaders(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, http.CanonicalHeaderKey(name))
+		}
+	}
+	return out
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// envList reads a comma-separated variable. It returns nil when key is unset.
+func envList(key string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
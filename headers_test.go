@@ -0,0 +1,93 @@
+package gintelemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanAttr(s sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestMiddleware_CapturesHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	cfg := NewTestConfig("test")
+	cfg.Exporter = ExporterNone
+	cfg.SpanProcessors = []sdktrace.SpanProcessor{recorder}
+	cfg.CaptureHeaders = HeaderConfig{
+		Request:  []string{"x-request-id", "Authorization"},
+		Response: []string{"Content-Type"},
+	}
+	tel, router, err := Start(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = tel.Shutdown(context.Background()) })
+	router.GET("/orders/:id", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"http.request.header.x-request-id", "abc"},
+		{"http.request.header.authorization", "[REDACTED]"},
+		{"http.response.header.content-type", "application/json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		v, ok := spanAttr(spans[0], tt.key)
+		if !ok {
+			t.Errorf("span has no %s attribute", tt.key)
+			continue
+		}
+		if got := v.AsStringSlice(); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s = %v, want [%s]", tt.key, got, tt.want)
+		}
+	}
+}
+
+// The server span context must outlive otelgin for the metric exemplars.
+func TestServerRequests_KeepsSpanContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	tracing := otelgin.Middleware("test", otelgin.WithTracerProvider(serverTracerProvider{tp}))
+
+	var inHandler, afterTracing trace.SpanContext
+	router := gin.New()
+	router.Use(serverRequests(nil, func(c *gin.Context) {
+		tracing(c)
+		afterTracing = serverSpanContext(c.Request.Context())
+	}))
+	router.GET("/", func(c *gin.Context) {
+		inHandler = trace.SpanContextFromContext(c.Request.Context())
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !inHandler.IsValid() || !afterTracing.Equal(inHandler) {
+		t.Errorf("span context after tracing = %v, want the server span %v", afterTracing, inHandler)
+	}
+}
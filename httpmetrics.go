@@ -0,0 +1,119 @@
+package gintelemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serverMetrics records the HTTP server metrics from the OpenTelemetry
+// semantic conventions for every request.
+type serverMetrics struct {
+	duration     metric.Float64Histogram
+	active       metric.Int64UpDownCounter
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+}
+
+func newServerMetrics(meter metric.Meter) (*serverMetrics, error) {
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10))
+	if err != nil {
+		return nil, err
+	}
+	active, err := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("Number of active HTTP server requests"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+	requestSize, err := meter.Int64Histogram("http.server.request.body.size",
+		metric.WithDescription("Size of HTTP server request bodies"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	responseSize, err := meter.Int64Histogram("http.server.response.body.size",
+		metric.WithDescription("Size of HTTP server response bodies"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	return &serverMetrics{
+		duration:     duration,
+		active:       active,
+		requestSize:  requestSize,
+		responseSize: responseSize,
+	}, nil
+}
+
+// middleware wraps next, the tracing middleware, with request metrics.
+// Requests are labelled with the route template rather than the path, and
+// requests that match no route share one series without http.route, so
+// that scanners probing random paths cannot create new series. Requests
+// matching filter are passed to next without metrics; next applies the same
+// filter to tracing.
+func (m *serverMetrics) middleware(next gin.HandlerFunc, filter *requestFilter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if filter.match(c) {
+			next(c)
+			return
+		}
+		start := time.Now()
+		ctx := c.Request.Context()
+		method := requestMethod(c.Request.Method)
+		scheme := semconv.URLSchemeKey.String("http")
+		if c.Request.TLS != nil {
+			scheme = semconv.URLSchemeKey.String("https")
+		}
+
+		activeAttrs := metric.WithAttributeSet(attribute.NewSet(method, scheme))
+		m.active.Add(ctx, 1, activeAttrs)
+		defer m.active.Add(ctx, -1, activeAttrs)
+
+		next(c)
+
+		status := c.Writer.Status()
+		attrs := []attribute.KeyValue{
+			method,
+			scheme,
+			semconv.HTTPResponseStatusCodeKey.Int(status),
+		}
+		if route := c.FullPath(); route != "" {
+			attrs = append(attrs, semconv.HTTPRouteKey.String(route))
+		}
+		if status >= http.StatusInternalServerError {
+			attrs = append(attrs, semconv.ErrorTypeKey.String(strconv.Itoa(status)))
+		}
+		opts := metric.WithAttributeSet(attribute.NewSet(attrs...))
+
+		// Link the measurements to the server span for exemplars.
+		if sc := serverSpanContext(ctx); sc.IsValid() {
+			ctx = trace.ContextWithSpanContext(ctx, sc)
+		}
+		m.duration.Record(ctx, time.Since(start).Seconds(), opts)
+		if c.Request.ContentLength >= 0 {
+			m.requestSize.Record(ctx, c.Request.ContentLength, opts)
+		}
+		m.responseSize.Record(ctx, int64(max(c.Writer.Size(), 0)), opts)
+	}
+}
+
+// requestMethod returns the http.request.method attribute, with
+// non-standard methods reported as _OTHER.
+func requestMethod(method string) attribute.KeyValue {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return semconv.HTTPRequestMethodKey.String(method)
+	}
+	return semconv.HTTPRequestMethodOther
+}
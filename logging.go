@@ -3,7 +3,6 @@ package gintelemetry
 import (
 	"context"
 	"log/slog"
-	"os"
 )
 
 const (
@@ -15,21 +14,21 @@ const (
 
 type Level = slog.Level
 
-// applyLevelFilter creates a logger that writes to both OTLP collector and stdout.
+// applyLevelFilter creates a logger that writes to both OTLP collector and the console.
 // This provides dual output: structured logs to the collector and console output for development.
-func applyLevelFilter(otelLogger *slog.Logger, level Level) *slog.Logger {
-	// Create stdout handler for console output
-	stdoutHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
-	})
-
-	// Combine OTLP and stdout handlers
+func applyLevelFilter(otelLogger *slog.Logger, level Level, console ConsoleConfig) *slog.Logger {
+	// Combine OTLP and console handlers. otelLogger is nil when logs are
+	// disabled, and the console handler is nil when it is turned off.
+	var handlers []slog.Handler
+	if otelLogger != nil {
+		handlers = append(handlers, otelLogger.Handler())
+	}
+	if consoleHandler := console.handler(level); consoleHandler != nil {
+		handlers = append(handlers, consoleHandler)
+	}
 	multiHandler := &multiHandler{
-		handlers: []slog.Handler{
-			otelLogger.Handler(),
-			stdoutHandler,
-		},
-		level: level,
+		handlers: handlers,
+		level:    level,
 	}
 
 	return slog.New(multiHandler)
@@ -0,0 +1,250 @@
+package gintelemetry
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Option changes one part of a Config. Options let shared packages bundle
+// settings that services combine with StartWith or NewWith.
+//
+// Example:
+//
+//	tel, router, err := gintelemetry.StartWith(ctx,
+//	    gintelemetry.WithConfig(teamdefaults.Config()),
+//	    gintelemetry.WithServiceName("checkout"),
+//	    gintelemetry.WithSampler(gintelemetry.SamplerParentBasedTraceIDRatio, 0.1),
+//	)
+type Option func(*Config)
+
+// StartWith is Start with a Config built from options. Options are applied
+// in order to an empty Config; unset fields still fall back to environment
+// variables and defaults.
+func StartWith(ctx context.Context, opts ...Option) (*Telemetry, *gin.Engine, error) {
+	return Start(ctx, configFrom(opts))
+}
+
+// NewWith is New with a Config built from options.
+func NewWith(ctx context.Context, opts ...Option) (*Telemetry, error) {
+	return New(ctx, configFrom(opts))
+}
+
+func configFrom(opts []Option) Config {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithConfig replaces the whole Config with base. Use it as the first
+// option to layer further options on top of a shared Config.
+func WithConfig(base Config) Option {
+	return func(c *Config) { *c = base }
+}
+
+// WithServiceName sets Config.ServiceName.
+func WithServiceName(name string) Option {
+	return func(c *Config) { c.ServiceName = name }
+}
+
+// WithServiceVersion sets Config.ServiceVersion.
+func WithServiceVersion(version string) Option {
+	return func(c *Config) { c.ServiceVersion = version }
+}
+
+// WithEnvironment sets Config.Environment.
+func WithEnvironment(env string) Option {
+	return func(c *Config) { c.Environment = env }
+}
+
+// WithEndpoint sets Config.Endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(c *Config) { c.Endpoint = endpoint }
+}
+
+// WithProtocol sets Config.Protocol.
+func WithProtocol(protocol Protocol) Option {
+	return func(c *Config) { c.Protocol = protocol }
+}
+
+// WithInsecure sets Config.Insecure.
+func WithInsecure() Option {
+	return func(c *Config) { c.Insecure = true }
+}
+
+// WithTLS sets Config.TLS.
+func WithTLS(tls TLSConfig) Option {
+	return func(c *Config) { c.TLS = tls }
+}
+
+// WithExporter sets Config.Exporter.
+func WithExporter(exporter Exporter) Option {
+	return func(c *Config) { c.Exporter = exporter }
+}
+
+// WithHeaders adds export headers to Config.Headers.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Config) { c.Headers = mergeMaps(c.Headers, headers) }
+}
+
+// WithCredentials sets Config.Credentials.
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(c *Config) { c.Credentials = provider }
+}
+
+// WithLogLevel sets Config.LogLevel and Config.LogLevelSet.
+func WithLogLevel(level Level) Option {
+	return func(c *Config) {
+		c.LogLevel = level
+		c.LogLevelSet = true
+	}
+}
+
+// WithConsole sets the format of console log output, keeping its writer.
+func WithConsole(format ConsoleFormat) Option {
+	return func(c *Config) { c.Console.Format = format }
+}
+
+// WithGlobalAttributes adds attributes to Config.GlobalAttributes.
+func WithGlobalAttributes(attrs map[string]string) Option {
+	return func(c *Config) { c.GlobalAttributes = mergeMaps(c.GlobalAttributes, attrs) }
+}
+
+// WithResource sets Config.Resource.
+func WithResource(res *resource.Resource) Option {
+	return func(c *Config) { c.Resource = res }
+}
+
+// WithDetectors sets Config.Detectors. Call it with no arguments to turn
+// resource detection off.
+func WithDetectors(detectors ...resource.Detector) Option {
+	return func(c *Config) { c.Detectors = append([]resource.Detector{}, detectors...) }
+}
+
+// WithSampler sets the head sampler and, for the ratio samplers, the ratio.
+func WithSampler(sampler Sampler, ratio float64) Option {
+	return func(c *Config) {
+		c.Sampling.Sampler = sampler
+		c.Sampling.Ratio = &ratio
+	}
+}
+
+// WithSamplingRules adds head sampling rules to Config.Sampling.
+func WithSamplingRules(rules ...SamplingRule) Option {
+	return func(c *Config) { c.Sampling.Rules = append(slices.Clip(c.Sampling.Rules), rules...) }
+}
+
+// WithTailSampling sets Config.TailSampling and enables it.
+func WithTailSampling(cfg TailSamplingConfig) Option {
+	return func(c *Config) {
+		c.TailSampling = cfg
+		c.TailSampling.Enabled = true
+	}
+}
+
+// WithSpanProcessor adds a span processor to Config.SpanProcessors.
+func WithSpanProcessor(p sdktrace.SpanProcessor) Option {
+	return func(c *Config) { c.SpanProcessors = append(slices.Clip(c.SpanProcessors), p) }
+}
+
+// WithPropagators sets Config.Propagators.
+func WithPropagators(propagators ...Propagator) Option {
+	return func(c *Config) { c.Propagators = propagators }
+}
+
+// WithTraces sets Config.Traces.
+func WithTraces(s SignalConfig) Option {
+	return func(c *Config) { c.Traces = s }
+}
+
+// WithMetrics sets Config.Metrics.
+func WithMetrics(s SignalConfig) Option {
+	return func(c *Config) { c.Metrics = s }
+}
+
+// WithLogs sets Config.Logs.
+func WithLogs(s SignalConfig) Option {
+	return func(c *Config) { c.Logs = s }
+}
+
+// WithCompression sets Config.Compression.
+func WithCompression(compression Compression) Option {
+	return func(c *Config) { c.Compression = compression }
+}
+
+// WithMetricInterval sets Config.MetricInterval and Config.MetricTimeout.
+// A zero timeout keeps the default.
+func WithMetricInterval(interval, timeout time.Duration) Option {
+	return func(c *Config) {
+		c.MetricInterval = interval
+		c.MetricTimeout = timeout
+	}
+}
+
+// WithMetricTemporality sets Config.MetricTemporality.
+func WithMetricTemporality(temporality Temporality) Option {
+	return func(c *Config) { c.MetricTemporality = temporality }
+}
+
+// WithViews adds metric views to Config.Views.
+func WithViews(views ...View) Option {
+	return func(c *Config) { c.Views = append(slices.Clip(c.Views), views...) }
+}
+
+// WithCardinalityLimit sets Config.Cardinality.
+func WithCardinalityLimit(cardinality CardinalityConfig) Option {
+	return func(c *Config) { c.Cardinality = cardinality }
+}
+
+// WithPrometheus enables Config.Prometheus with the given route, or
+// "/metrics" when path is empty, on the Gin router.
+func WithPrometheus(path string) Option {
+	return func(c *Config) { c.Prometheus = PrometheusConfig{Enabled: true, Path: path} }
+}
+
+// WithFilter sets Config.Filter.
+func WithFilter(filter FilterConfig) Option {
+	return func(c *Config) { c.Filter = filter }
+}
+
+// WithCaptureHeaders sets Config.CaptureHeaders.
+func WithCaptureHeaders(headers HeaderConfig) Option {
+	return func(c *Config) { c.CaptureHeaders = headers }
+}
+
+// WithRedaction enables Config.Redaction with the built-in patterns and the
+// given rules.
+func WithRedaction(rules ...RedactionRule) Option {
+	return func(c *Config) { c.Redaction = RedactionConfig{Enabled: true, Rules: rules} }
+}
+
+// WithRetry sets Config.Retry.
+func WithRetry(retry RetryConfig) Option {
+	return func(c *Config) { c.Retry = retry }
+}
+
+// WithShutdownTimeout sets Config.ShutdownTimeout.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(c *Config) { c.ShutdownTimeout = timeout }
+}
+
+// WithDisabled sets Config.Disabled, turning off all signals.
+func WithDisabled() Option {
+	return func(c *Config) { c.Disabled = true }
+}
+
+// WithDegradeOnError sets Config.DegradeOnError.
+func WithDegradeOnError() Option {
+	return func(c *Config) { c.DegradeOnError = true }
+}
+
+// WithGlobalProvider sets Config.SetGlobalProvider.
+func WithGlobalProvider() Option {
+	return func(c *Config) { c.SetGlobalProvider = true }
+}
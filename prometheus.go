@@ -0,0 +1,79 @@
+package gintelemetry
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// PrometheusConfig serves metrics for Prometheus to scrape. It works
+// alongside the metrics exporter, so metrics can be pushed over OTLP and
+// scraped at the same time; set Metrics.Exporter to ExporterNone to only
+// scrape.
+//
+// Responses use the OpenMetrics format when the scraper asks for it, which
+// includes exemplars linking metric points to traces. Resource attributes
+// are reported as the target_info metric.
+type PrometheusConfig struct {
+	// Enabled turns the scrape endpoint on.
+	Enabled bool
+
+	// Path is the route that serves metrics. Defaults to "/metrics".
+	Path string
+
+	// Addr serves metrics on a separate listener at this address, e.g.
+	// ":9464", instead of on the Gin router. Use it to keep metrics off the
+	// public port.
+	Addr string
+}
+
+func (p *PrometheusConfig) validate() error {
+	if !p.Enabled {
+		return nil
+	}
+	if p.Path == "" {
+		p.Path = "/metrics"
+	}
+	if !strings.HasPrefix(p.Path, "/") {
+		return fmt.Errorf("gintelemetry: Prometheus Path %q must start with /", p.Path)
+	}
+	return nil
+}
+
+// newPrometheus creates a metric reader backed by its own registry and the
+// handler that serves it.
+func newPrometheus() (sdkmetric.Reader, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	reader, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	return reader, handler, nil
+}
+
+// startMetricsServer serves handler at path on a new listener at addr.
+func startMetricsServer(addr, path string, handler http.Handler) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			otel.Handle(err)
+		}
+	}()
+	return server, nil
+}
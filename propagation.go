@@ -0,0 +1,162 @@
+package gintelemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Propagator identifies a context propagation format used to read incoming
+// trace headers and write outgoing ones. Values match the names accepted by
+// the OTEL_PROPAGATORS environment variable.
+type Propagator string
+
+const (
+	// PropagatorTraceContext is the W3C traceparent/tracestate format.
+	PropagatorTraceContext Propagator = "tracecontext"
+
+	// PropagatorBaggage is the W3C baggage format.
+	PropagatorBaggage Propagator = "baggage"
+
+	// PropagatorB3 is the Zipkin B3 single-header format.
+	PropagatorB3 Propagator = "b3"
+
+	// PropagatorB3Multi is the Zipkin B3 multi-header format.
+	PropagatorB3Multi Propagator = "b3multi"
+
+	// PropagatorJaeger is the Jaeger uber-trace-id format.
+	PropagatorJaeger Propagator = "jaeger"
+
+	// PropagatorXRay is the AWS X-Ray X-Amzn-Trace-Id format.
+	PropagatorXRay Propagator = "xray"
+
+	// PropagatorCloudTrace is the Google Cloud X-Cloud-Trace-Context format.
+	PropagatorCloudTrace Propagator = "cloudtrace"
+
+	// PropagatorNone disables context propagation entirely.
+	PropagatorNone Propagator = "none"
+)
+
+// defaultPropagators mirrors the OpenTelemetry specification default.
+var defaultPropagators = []Propagator{PropagatorTraceContext, PropagatorBaggage}
+
+// parsePropagators parses a comma-separated OTEL_PROPAGATORS value.
+func parsePropagators(value string) []Propagator {
+	var props []Propagator
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name != "" {
+			props = append(props, Propagator(name))
+		}
+	}
+	return props
+}
+
+// validatePropagator reports whether p is a known propagation format.
+func validatePropagator(p Propagator) error {
+	switch p {
+	case PropagatorTraceContext, PropagatorBaggage, PropagatorB3, PropagatorB3Multi,
+		PropagatorJaeger, PropagatorXRay, PropagatorCloudTrace, PropagatorNone:
+		return nil
+	}
+	return fmt.Errorf("gintelemetry: unknown propagator %q", p)
+}
+
+// newPropagator builds a composite propagator from the configured formats.
+func newPropagator(props []Propagator) (propagation.TextMapPropagator, error) {
+	var list []propagation.TextMapPropagator
+	for _, p := range props {
+		switch p {
+		case PropagatorTraceContext:
+			list = append(list, propagation.TraceContext{})
+		case PropagatorBaggage:
+			list = append(list, propagation.Baggage{})
+		case PropagatorB3:
+			list = append(list, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			list = append(list, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			list = append(list, jaeger.Jaeger{})
+		case PropagatorXRay:
+			list = append(list, xray.Propagator{})
+		case PropagatorCloudTrace:
+			list = append(list, cloudTracePropagator{})
+		case PropagatorNone:
+			return propagation.NewCompositeTextMapPropagator(), nil
+		default:
+			return nil, validatePropagator(p)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(list...), nil
+}
+
+const cloudTraceHeader = "X-Cloud-Trace-Context"
+
+// cloudTracePropagator implements the Google Cloud X-Cloud-Trace-Context
+// header format: TRACE_ID/SPAN_ID;o=OPTIONS, where SPAN_ID is decimal.
+type cloudTracePropagator struct{}
+
+func (cloudTracePropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	spanID := sc.SpanID()
+	sampled := 0
+	if sc.IsSampled() {
+		sampled = 1
+	}
+	carrier.Set(cloudTraceHeader, fmt.Sprintf("%s/%d;o=%d",
+		sc.TraceID(), binary.BigEndian.Uint64(spanID[:]), sampled))
+}
+
+func (cloudTracePropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, ok := parseCloudTraceContext(carrier.Get(cloudTraceHeader))
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func (cloudTracePropagator) Fields() []string {
+	return []string{cloudTraceHeader}
+}
+
+func parseCloudTraceContext(value string) (trace.SpanContext, bool) {
+	traceHex, rest, found := strings.Cut(value, "/")
+	if !found {
+		return trace.SpanContext{}, false
+	}
+	traceID, err := trace.TraceIDFromHex(traceHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	spanDec, options, _ := strings.Cut(rest, ";")
+	spanNum, err := strconv.ParseUint(spanDec, 10, 64)
+	if err != nil || spanNum == 0 {
+		return trace.SpanContext{}, false
+	}
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], spanNum)
+
+	var flags trace.TraceFlags
+	if options == "o=1" {
+		flags = trace.FlagsSampled
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	}), true
+}
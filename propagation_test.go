@@ -0,0 +1,149 @@
+package gintelemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCloudTracePropagator_Extract(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("105445aa7843bc8bf206b12000100000")
+
+	tests := []struct {
+		header      string
+		wantValid   bool
+		wantSpanID  string
+		wantSampled bool
+	}{
+		{header: "105445aa7843bc8bf206b12000100000/1;o=1", wantValid: true, wantSpanID: "0000000000000001", wantSampled: true},
+		{header: "105445aa7843bc8bf206b12000100000/18446744073709551615;o=0", wantValid: true, wantSpanID: "ffffffffffffffff"},
+		{header: "105445aa7843bc8bf206b12000100000/255", wantValid: true, wantSpanID: "00000000000000ff"},
+		{header: ""},
+		{header: "105445aa7843bc8bf206b12000100000"},
+		{header: "105445aa7843bc8bf206b12000100000/0;o=1"},
+		{header: "105445aa7843bc8bf206b12000100000/abc;o=1"},
+		{header: "105445aa7843bc8bf206b12000100000/18446744073709551616;o=1"},
+		{header: "00000000000000000000000000000000/1;o=1"},
+		{header: "not-hex/1;o=1"},
+	}
+
+	for _, tt := range tests {
+		carrier := propagation.MapCarrier{cloudTraceHeader: tt.header}
+		sc := trace.SpanContextFromContext(cloudTracePropagator{}.Extract(context.Background(), carrier))
+		if sc.IsValid() != tt.wantValid {
+			t.Errorf("Extract(%q) valid = %v, want %v", tt.header, sc.IsValid(), tt.wantValid)
+			continue
+		}
+		if !tt.wantValid {
+			continue
+		}
+		if sc.TraceID() != traceID || sc.SpanID().String() != tt.wantSpanID || sc.IsSampled() != tt.wantSampled || !sc.IsRemote() {
+			t.Errorf("Extract(%q) = %s/%s sampled=%v remote=%v, want %s/%s sampled=%v remote=true",
+				tt.header, sc.TraceID(), sc.SpanID(), sc.IsSampled(), sc.IsRemote(), traceID, tt.wantSpanID, tt.wantSampled)
+		}
+	}
+}
+
+func TestCloudTracePropagator_RoundTrip(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x10, 0x54, 0x45, 0xaa},
+		SpanID:     trace.SpanID{0, 0, 0, 0, 0, 0, 0x01, 0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	carrier := propagation.MapCarrier{}
+	cloudTracePropagator{}.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+
+	want := "105445aa000000000000000000000000/258;o=1"
+	if got := carrier.Get(cloudTraceHeader); got != want {
+		t.Fatalf("Inject() header = %q, want %q", got, want)
+	}
+	got := trace.SpanContextFromContext(cloudTracePropagator{}.Extract(context.Background(), carrier))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || got.TraceFlags() != sc.TraceFlags() {
+		t.Errorf("round trip = %v, want %v", got, sc)
+	}
+}
+
+func TestCloudTracePropagator_InjectInvalid(t *testing.T) {
+	carrier := propagation.MapCarrier{}
+	cloudTracePropagator{}.Inject(context.Background(), carrier)
+	if len(carrier) != 0 {
+		t.Errorf("Inject() without a span context set %v", carrier)
+	}
+}
+
+func TestNewPropagator(t *testing.T) {
+	tests := []struct {
+		props      []Propagator
+		wantFields []string
+		wantErr    bool
+	}{
+		{props: defaultPropagators, wantFields: []string{"traceparent", "tracestate", "baggage"}},
+		{props: []Propagator{PropagatorCloudTrace}, wantFields: []string{cloudTraceHeader}},
+		{props: []Propagator{PropagatorTraceContext, PropagatorNone}},
+		{props: []Propagator{"zipkin"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		p, err := newPropagator(tt.props)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newPropagator(%v) error = %v, wantErr %v", tt.props, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got := p.Fields(); len(got) != len(tt.wantFields) {
+			t.Errorf("newPropagator(%v) fields = %v, want %v", tt.props, got, tt.wantFields)
+		}
+	}
+}
+
+func TestParsePropagators(t *testing.T) {
+	got := parsePropagators(" TraceContext, ,cloudtrace ")
+	if len(got) != 2 || got[0] != PropagatorTraceContext || got[1] != PropagatorCloudTrace {
+		t.Errorf("parsePropagators() = %v, want [tracecontext cloudtrace]", got)
+	}
+}
+
+// Unknown propagators are reported by validate together with other errors.
+func TestConfig_UnknownPropagators(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		env  string
+		want []string
+	}{
+		{
+			name: "field",
+			cfg:  Config{Propagators: []Propagator{PropagatorB3, "zipkin", "ot"}},
+			want: []string{`unknown propagator "zipkin"`, `unknown propagator "ot"`, "Timeout cannot be negative"},
+		},
+		{
+			name: "env",
+			env:  "tracecontext,zipkin",
+			want: []string{`unknown propagator "zipkin"`, "Timeout cannot be negative"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_PROPAGATORS", tt.env)
+			cfg := tt.cfg
+			cfg.ServiceName = "test"
+			cfg.Exporter = ExporterNone
+			cfg.Timeout = -1
+			err := cfg.validate()
+			if err == nil {
+				t.Fatal("validate() accepted an unknown propagator")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validate() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package gintelemetry
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactPattern names a built-in detector for sensitive values.
+type RedactPattern string
+
+const (
+	// RedactEmails detects email addresses.
+	RedactEmails RedactPattern = "email"
+
+	// RedactCardNumbers detects payment card numbers that pass the Luhn check.
+	RedactCardNumbers RedactPattern = "card_number"
+
+	// RedactTokens detects bearer tokens and JWTs.
+	RedactTokens RedactPattern = "token"
+
+	// RedactIPs detects IPv4 and IPv6 addresses.
+	RedactIPs RedactPattern = "ip"
+)
+
+// RedactionStrategy selects how sensitive values are replaced.
+type RedactionStrategy string
+
+const (
+	// RedactMask replaces values with "[REDACTED]" (default).
+	RedactMask RedactionStrategy = "mask"
+
+	// RedactHash replaces values with "sha256:" and the first 16 hex digits
+	// of their HMAC-SHA256 keyed with HashKey, so equal values can still be
+	// correlated.
+	RedactHash RedactionStrategy = "hash"
+)
+
+// RedactionConfig removes sensitive values from span names, attributes,
+// events and links, log messages and attributes, and metric attributes
+// before they are exported or written to the console.
+//
+// Example:
+//
+//	Redaction: gintelemetry.RedactionConfig{
+//	    Enabled:  true,
+//	    Strategy: gintelemetry.RedactHash,
+//	    Rules: []gintelemetry.RedactionRule{
+//	        {Key: "user.name"},
+//	        {Pattern: `\bACC-\d{8}\b`},
+//	    },
+//	}
+type RedactionConfig struct {
+	// Enabled turns redaction on.
+	Enabled bool
+
+	// Patterns selects the built-in detectors applied to every string value.
+	// Defaults to all of them.
+	Patterns []RedactPattern
+
+	// Rules add custom redaction by attribute key or regular expression.
+	Rules []RedactionRule
+
+	// Strategy selects masking or hashing. Defaults to RedactMask.
+	Strategy RedactionStrategy
+
+	// HashKey keys the hash with HMAC-SHA256, so that values cannot be
+	// recovered by hashing guesses. Required with RedactHash; use the same
+	// key across replicas for their hashes to match.
+	HashKey string
+}
+
+// RedactionRule redacts values by attribute key, by content, or both.
+type RedactionRule struct {
+	// Key redacts the whole value of attributes whose key matches,
+	// case-insensitively. "*" matches any run of characters, e.g.
+	// "*.password".
+	Key string
+
+	// Pattern is a regular expression; matching parts of string values are
+	// redacted. With Key set, only values of matching attributes are checked.
+	Pattern string
+}
+
+func (r *RedactionConfig) validate() error {
+	if !r.Enabled {
+		return nil
+	}
+	if r.Patterns == nil {
+		r.Patterns = []RedactPattern{RedactEmails, RedactCardNumbers, RedactTokens, RedactIPs}
+	}
+	for _, p := range r.Patterns {
+		if _, ok := builtinPatterns[p]; !ok {
+			return fmt.Errorf("gintelemetry: unknown redaction pattern %q", p)
+		}
+	}
+	for i, rule := range r.Rules {
+		if rule.Key == "" && rule.Pattern == "" {
+			return fmt.Errorf("gintelemetry: Redaction.Rules[%d] needs a Key or a Pattern", i)
+		}
+		if _, err := path.Match(rule.Key, ""); err != nil {
+			return fmt.Errorf("gintelemetry: invalid redaction key %q: %w", rule.Key, err)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("gintelemetry: invalid redaction pattern %q: %w", rule.Pattern, err)
+		}
+	}
+	switch r.Strategy {
+	case "":
+		r.Strategy = RedactMask
+	case RedactMask:
+	case RedactHash:
+		// An unkeyed hash of a short value such as an email or card number
+		// is reversed by hashing candidates.
+		if r.HashKey == "" {
+			return fmt.Errorf("gintelemetry: Redaction.HashKey is required with RedactHash")
+		}
+	default:
+		return fmt.Errorf("gintelemetry: unsupported redaction strategy %q", r.Strategy)
+	}
+	return nil
+}
+
+// detector finds sensitive substrings. valid, when set, rejects false
+// positives such as numbers that fail the Luhn check.
+type detector struct {
+	re    *regexp.Regexp
+	valid func(string) bool
+}
+
+var builtinPatterns = map[RedactPattern][]detector{
+	RedactEmails: {{re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)}},
+	RedactCardNumbers: {{
+		re:    regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid: luhnValid,
+	}},
+	RedactTokens: {
+		{re: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`)},
+		{re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)},
+	},
+	RedactIPs: {
+		{re: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), valid: isIP},
+		{re: regexp.MustCompile(`(?i)\b(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}\b`), valid: isIP},
+	},
+}
+
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// redactor applies a validated RedactionConfig.
+type redactor struct {
+	detectors []detector
+	keys      []string       // lowercased key globs that redact whole values
+	keyed     []keyedPattern // patterns limited to some keys
+	hashKey   []byte
+	hash      bool
+}
+
+type keyedPattern struct {
+	key string // lowercased glob, "" for any key
+	re  *regexp.Regexp
+}
+
+// newRedactor returns nil when redaction is disabled.
+func newRedactor(cfg RedactionConfig) *redactor {
+	if !cfg.Enabled {
+		return nil
+	}
+	r := &redactor{hash: cfg.Strategy == RedactHash, hashKey: []byte(cfg.HashKey)}
+	for _, p := range cfg.Patterns {
+		r.detectors = append(r.detectors, builtinPatterns[p]...)
+	}
+	for _, rule := range cfg.Rules {
+		key := strings.ToLower(rule.Key)
+		if rule.Pattern == "" {
+			r.keys = append(r.keys, key)
+			continue
+		}
+		r.keyed = append(r.keyed, keyedPattern{key: key, re: regexp.MustCompile(rule.Pattern)})
+	}
+	return r
+}
+
+// replacement returns what a sensitive value s is replaced with.
+func (r *redactor) replacement(s string) string {
+	if !r.hash {
+		return "[REDACTED]"
+	}
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(s))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func keyMatches(glob, key string) bool {
+	ok, _ := path.Match(glob, key)
+	return ok
+}
+
+// redactsKey reports whether the whole value of key is redacted.
+func (r *redactor) redactsKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, glob := range r.keys {
+		if keyMatches(glob, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// text redacts the sensitive parts of s, the value of key ("" for log
+// messages and span names).
+func (r *redactor) text(key, s string) string {
+	if key != "" && r.redactsKey(key) {
+		return r.replacement(s)
+	}
+	for _, d := range r.detectors {
+		s = d.re.ReplaceAllStringFunc(s, func(m string) string {
+			if d.valid != nil && !d.valid(m) {
+				return m
+			}
+			return r.replacement(m)
+		})
+	}
+	lower := strings.ToLower(key)
+	for _, p := range r.keyed {
+		if p.key == "" || keyMatches(p.key, lower) {
+			s = p.re.ReplaceAllStringFunc(s, r.replacement)
+		}
+	}
+	return s
+}
+
+// attribute redacts kv. Non-string values are only redacted by key.
+func (r *redactor) attribute(kv attribute.KeyValue) attribute.KeyValue {
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		return attribute.String(string(kv.Key), r.text(string(kv.Key), kv.Value.AsString()))
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = r.text(string(kv.Key), v)
+		}
+		return attribute.StringSlice(string(kv.Key), out)
+	}
+	if r.redactsKey(string(kv.Key)) {
+		return attribute.String(string(kv.Key), r.replacement(kv.Value.Emit()))
+	}
+	return kv
+}
+
+func (r *redactor) attributes(kvs []attribute.KeyValue) []attribute.KeyValue {
+	if len(kvs) == 0 {
+		return kvs
+	}
+	out := make([]attribute.KeyValue, len(kvs))
+	for i, kv := range kvs {
+		out[i] = r.attribute(kv)
+	}
+	return out
+}
+
+// set redacts the attributes of a metric measurement.
+func (r *redactor) set(s attribute.Set) attribute.Set {
+	if r == nil || s.Len() == 0 {
+		return s
+	}
+	return attribute.NewSet(r.attributes(s.ToSlice())...)
+}
+
+// slogAttr redacts a log attribute, recursing into groups.
+func (r *redactor) slogAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.text(a.Key, a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		out := make([]any, len(group))
+		for i, g := range group {
+			out[i] = r.slogAttr(g)
+		}
+		return slog.Group(a.Key, out...)
+	}
+	if r.redactsKey(a.Key) {
+		return slog.String(a.Key, r.replacement(a.Value.String()))
+	}
+	if a.Value.Kind() == slog.KindAny {
+		// Errors and other values are logged by their text, which may
+		// contain sensitive data.
+		if s := a.Value.String(); r.text(a.Key, s) != s {
+			return slog.String(a.Key, r.text(a.Key, s))
+		}
+	}
+	return a
+}
+
+// redactingProcessor hands redacted copies of ended spans to next, the
+// processor that exports them.
+type redactingProcessor struct {
+	next     sdktrace.SpanProcessor
+	redactor *redactor
+}
+
+func (p *redactingProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+func (p *redactingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	r := p.redactor
+	status := s.Status()
+	status.Description = r.text("", status.Description)
+	events := s.Events()
+	redactedEvents := make([]sdktrace.Event, len(events))
+	for i, e := range events {
+		e.Name = r.text("", e.Name)
+		e.Attributes = r.attributes(e.Attributes)
+		redactedEvents[i] = e
+	}
+	links := s.Links()
+	redactedLinks := make([]sdktrace.Link, len(links))
+	for i, l := range links {
+		l.Attributes = r.attributes(l.Attributes)
+		redactedLinks[i] = l
+	}
+	p.next.OnEnd(redactedSpan{
+		ReadOnlySpan: s,
+		name:         r.text("", s.Name()),
+		attributes:   r.attributes(s.Attributes()),
+		events:       redactedEvents,
+		links:        redactedLinks,
+		status:       status,
+	})
+}
+
+func (p *redactingProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *redactingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	name       string
+	attributes []attribute.KeyValue
+	events     []sdktrace.Event
+	links      []sdktrace.Link
+	status     sdktrace.Status
+}
+
+func (s redactedSpan) Name() string                     { return s.name }
+func (s redactedSpan) Attributes() []attribute.KeyValue { return s.attributes }
+func (s redactedSpan) Events() []sdktrace.Event         { return s.events }
+func (s redactedSpan) Links() []sdktrace.Link           { return s.links }
+func (s redactedSpan) Status() sdktrace.Status          { return s.status }
+
+// redactingHandler redacts log messages and attributes before passing
+// records to the console and OTLP handlers.
+type redactingHandler struct {
+	next     slog.Handler
+	redactor *redactor
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	out := slog.NewRecord(rec.Time, rec.Level, h.redactor.text("", rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redactor.slogAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactor.slogAttr(a)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), redactor: h.redactor}
+}
@@ -0,0 +1,205 @@
+package gintelemetry
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// newTestRedactor returns the redactor for cfg, enabled and validated.
+func newTestRedactor(t *testing.T, cfg RedactionConfig) *redactor {
+	t.Helper()
+	cfg.Enabled = true
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	return newRedactor(cfg)
+}
+
+func TestRedactor_Patterns(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"email", "sent to jane.doe+x@example.co.uk today", "sent to [REDACTED] today"},
+		{"card number", "card 4111 1111 1111 1111 declined", "card [REDACTED] declined"},
+		{"card number with dashes", "4111-1111-1111-1111", "[REDACTED]"},
+		{"number failing Luhn", "order 4111 1111 1111 1112", "order 4111 1111 1111 1112"},
+		{"bearer token", "Authorization: Bearer abc.DEF-123", "Authorization: [REDACTED]"},
+		{"JWT", "token=eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig", "token=[REDACTED]"},
+		{"IPv4", "from 10.0.0.12", "from [REDACTED]"},
+		{"invalid IPv4", "version 999.1.2.3", "version 999.1.2.3"},
+		{"IPv6", "from 2001:db8::1", "from [REDACTED]"},
+		{"plain text", "GET /orders/42", "GET /orders/42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.text("", tt.in); got != tt.want {
+				t.Errorf("text(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Rules(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{
+		Patterns: []RedactPattern{},
+		Rules: []RedactionRule{
+			{Key: "*.password"},
+			{Pattern: `\bACC-\d{8}\b`},
+			{Key: "db.statement", Pattern: `'[^']*'`},
+		},
+	})
+
+	tests := []struct {
+		key  string
+		in   string
+		want string
+	}{
+		{"user.password", "hunter2", "[REDACTED]"},
+		{"User.PASSWORD", "hunter2", "[REDACTED]"},
+		{"note", "account ACC-12345678 closed", "account [REDACTED] closed"},
+		{"db.statement", "SELECT * FROM users WHERE name = 'jane'", "SELECT * FROM users WHERE name = [REDACTED]"},
+		{"note", "name = 'jane'", "name = 'jane'"},
+	}
+
+	for _, tt := range tests {
+		if got := r.text(tt.key, tt.in); got != tt.want {
+			t.Errorf("text(%q, %q) = %q, want %q", tt.key, tt.in, got, tt.want)
+		}
+	}
+
+	// Non-string values are redacted by key only.
+	if got := r.attribute(attribute.Int("user.password", 1234)); got.Value.AsString() != "[REDACTED]" {
+		t.Errorf("attribute(user.password=1234) = %v, want redacted", got.Value.Emit())
+	}
+	if got := r.attribute(attribute.Int("http.status_code", 200)); got.Value.AsInt64() != 200 {
+		t.Errorf("attribute(http.status_code=200) = %v, want unchanged", got.Value.Emit())
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"4111111111111111", true},
+		{"4111 1111 1111 1111", true},
+		{"5500-0000-0000-0004", true},
+		{"4111111111111112", false},
+		{"1234567890123", false},
+	}
+	for _, tt := range tests {
+		if got := luhnValid(tt.in); got != tt.want {
+			t.Errorf("luhnValid(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactor_Hash(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{Strategy: RedactHash, HashKey: "k1"})
+	other := newTestRedactor(t, RedactionConfig{Strategy: RedactHash, HashKey: "k2"})
+
+	a, b := r.text("", "jane@example.com"), r.text("", "jane@example.com")
+	if a != b {
+		t.Errorf("hashes of equal values differ: %q, %q", a, b)
+	}
+	if !strings.HasPrefix(a, "sha256:") || len(a) != len("sha256:")+16 {
+		t.Errorf("hash = %q, want sha256: and 16 hex digits", a)
+	}
+	if c := r.text("", "john@example.com"); c == a {
+		t.Errorf("hashes of different values match: %q", c)
+	}
+	if c := other.text("", "jane@example.com"); c == a {
+		t.Errorf("hashes with different keys match: %q", c)
+	}
+}
+
+func TestRedactionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RedactionConfig
+		wantErr bool
+	}{
+		{name: "defaults", cfg: RedactionConfig{}},
+		{name: "hash with key", cfg: RedactionConfig{Strategy: RedactHash, HashKey: "secret"}},
+		{name: "hash without key", cfg: RedactionConfig{Strategy: RedactHash}, wantErr: true},
+		{name: "unknown strategy", cfg: RedactionConfig{Strategy: "rot13"}, wantErr: true},
+		{name: "unknown pattern", cfg: RedactionConfig{Patterns: []RedactPattern{"ssn"}}, wantErr: true},
+		{name: "empty rule", cfg: RedactionConfig{Rules: []RedactionRule{{}}}, wantErr: true},
+		{name: "invalid regexp", cfg: RedactionConfig{Rules: []RedactionRule{{Pattern: "("}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Enabled = true
+			err := tt.cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedactor_SlogAttr(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{Rules: []RedactionRule{{Key: "token"}}})
+
+	got := r.slogAttr(slog.Group("user", slog.String("email", "jane@example.com"), slog.Int("token", 7)))
+	want := "[email=[REDACTED] token=[REDACTED]]"
+	if got.Value.String() != want {
+		t.Errorf("slogAttr() = %s, want %s", got.Value, want)
+	}
+}
+
+// Metric() instruments are redacted; instruments created from the raw
+// MeterProvider() are documented to bypass redaction.
+func TestRedaction_MeterProviders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := NewTestConfig("test")
+	cfg.Exporter = ExporterNone
+	cfg.Prometheus = PrometheusConfig{Enabled: true}
+	cfg.Redaction = RedactionConfig{Enabled: true}
+	tel, router, err := Start(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = tel.Shutdown(context.Background()) })
+
+	ctx := context.Background()
+	email := metric.WithAttributes(attribute.String("user", "ann@example.com"))
+	tel.Metric().Counter("wrapped").Add(ctx, 1, email)
+	raw, err := tel.MeterProvider().Meter("test").Int64Counter("raw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.Add(ctx, 1, email)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	var wrapped, unwrapped string
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		switch {
+		case strings.HasPrefix(line, "wrapped_total{"):
+			wrapped = line
+		case strings.HasPrefix(line, "raw_total{"):
+			unwrapped = line
+		}
+	}
+	if !strings.Contains(wrapped, `user="[REDACTED]"`) {
+		t.Errorf("Metric() series = %q, want the user redacted", wrapped)
+	}
+	if !strings.Contains(unwrapped, `user="ann@example.com"`) {
+		t.Errorf("MeterProvider() series = %q, want the raw user", unwrapped)
+	}
+}
@@ -0,0 +1,179 @@
+package gintelemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+)
+
+// optionDetector runs a set of the SDK's resource options as one detector.
+type optionDetector []resource.Option
+
+func (d optionDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	return resource.New(ctx, d...)
+}
+
+// detectorFunc adapts a function to resource.Detector.
+type detectorFunc func(ctx context.Context) (*resource.Resource, error)
+
+func (f detectorFunc) Detect(ctx context.Context) (*resource.Resource, error) {
+	return f(ctx)
+}
+
+// DetectHost adds host.name.
+func DetectHost() resource.Detector {
+	return optionDetector{resource.WithHost()}
+}
+
+// DetectOS adds os.type and os.description.
+func DetectOS() resource.Detector {
+	return optionDetector{resource.WithOS()}
+}
+
+// DetectProcess adds the process ID, executable name and path, and the Go
+// runtime name and version. Command-line arguments are not included since
+// they often contain secrets.
+func DetectProcess() resource.Detector {
+	return optionDetector{
+		resource.WithProcessPID(),
+		resource.WithProcessExecutableName(),
+		resource.WithProcessExecutablePath(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithProcessRuntimeDescription(),
+	}
+}
+
+// DetectContainer adds container.id, read from the process cgroup. It adds
+// nothing outside a container.
+func DetectContainer() resource.Detector {
+	return optionDetector{resource.WithContainerID()}
+}
+
+// DetectKubernetes adds the pod name and UID, namespace and node name from
+// environment variables set with the Kubernetes downward API:
+//
+//	env:
+//	- name: K8S_POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	- name: K8S_NAMESPACE_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	- name: K8S_NODE_NAME
+//	  valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+//	- name: K8S_POD_UID
+//	  valueFrom: {fieldRef: {fieldPath: metadata.uid}}
+//
+// The common POD_NAME, POD_NAMESPACE, NODE_NAME and POD_UID names are also
+// accepted.
+func DetectKubernetes() resource.Detector {
+	return detectorFunc(func(context.Context) (*resource.Resource, error) {
+		fields := []struct {
+			key attribute.Key
+			env []string
+		}{
+			{semconv.K8SPodNameKey, []string{"K8S_POD_NAME", "POD_NAME"}},
+			{semconv.K8SPodUIDKey, []string{"K8S_POD_UID", "POD_UID"}},
+			{semconv.K8SNamespaceNameKey, []string{"K8S_NAMESPACE_NAME", "POD_NAMESPACE"}},
+			{semconv.K8SNodeNameKey, []string{"K8S_NODE_NAME", "NODE_NAME"}},
+		}
+		var attrs []attribute.KeyValue
+		for _, f := range fields {
+			for _, name := range f.env {
+				if v := os.Getenv(name); v != "" {
+					attrs = append(attrs, f.key.String(v))
+					break
+				}
+			}
+		}
+		return resource.NewSchemaless(attrs...), nil
+	})
+}
+
+// DetectBuildInfo adds the VCS revision the binary was built from, as
+// recorded by the Go toolchain, and service.version from the main module
+// version when it is a tagged release.
+func DetectBuildInfo() resource.Detector {
+	return detectorFunc(func(context.Context) (*resource.Resource, error) {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return resource.Empty(), nil
+		}
+		var attrs []attribute.KeyValue
+		if v := info.Main.Version; v != "" && v != "(devel)" {
+			attrs = append(attrs, semconv.ServiceVersionKey.String(v))
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				attrs = append(attrs, semconv.VCSRefHeadRevisionKey.String(s.Value))
+			case "vcs.modified":
+				attrs = append(attrs, attribute.Bool("vcs.modified", s.Value == "true"))
+			}
+		}
+		return resource.NewSchemaless(attrs...), nil
+	})
+}
+
+// DefaultDetectors returns the detectors used when Config.Detectors is nil.
+func DefaultDetectors() []resource.Detector {
+	return []resource.Detector{
+		DetectHost(),
+		DetectOS(),
+		DetectProcess(),
+		DetectContainer(),
+		DetectKubernetes(),
+		DetectBuildInfo(),
+	}
+}
+
+// newResource builds the resource shared by all signals. Later sources win:
+// SDK defaults and OTEL_RESOURCE_ATTRIBUTES, detectors, Config.Resource,
+// the service fields, then GlobalAttributes.
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	detectors := cfg.Detectors
+	if detectors == nil {
+		detectors = DefaultDetectors()
+	}
+	detected, err := resource.New(ctx, resource.WithDetectors(detectors...))
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, fmt.Errorf("failed to detect resource: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+		semconv.ServiceInstanceIDKey.String(cfg.ServiceInstanceID),
+	}
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(cfg.ServiceVersion))
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentNameKey.String(cfg.Environment))
+	}
+	for k, v := range cfg.GlobalAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	res := resource.Default()
+	for _, r := range []*resource.Resource{detected, cfg.Resource, resource.NewSchemaless(attrs...)} {
+		if res, err = resource.Merge(res, r); err != nil {
+			return nil, fmt.Errorf("failed to create resource: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// newInstanceID returns a random UUID (version 4) for service.instance.id.
+func newInstanceID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
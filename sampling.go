@@ -0,0 +1,212 @@
+package gintelemetry
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+)
+
+// Sampler identifies a head sampling strategy. Values match the names
+// accepted by the OTEL_TRACES_SAMPLER environment variable.
+type Sampler string
+
+const (
+	// SamplerAlwaysOn records every trace.
+	SamplerAlwaysOn Sampler = "always_on"
+
+	// SamplerAlwaysOff drops every trace.
+	SamplerAlwaysOff Sampler = "always_off"
+
+	// SamplerTraceIDRatio records a fraction of traces based on trace ID.
+	SamplerTraceIDRatio Sampler = "traceidratio"
+
+	// SamplerParentBasedAlwaysOn follows the parent decision and records
+	// every root span (default).
+	SamplerParentBasedAlwaysOn Sampler = "parentbased_always_on"
+
+	// SamplerParentBasedAlwaysOff follows the parent decision and drops
+	// every root span.
+	SamplerParentBasedAlwaysOff Sampler = "parentbased_always_off"
+
+	// SamplerParentBasedTraceIDRatio follows the parent decision and records
+	// a fraction of root spans.
+	SamplerParentBasedTraceIDRatio Sampler = "parentbased_traceidratio"
+)
+
+// SamplingConfig configures head sampling for traces.
+type SamplingConfig struct {
+	// Sampler selects the base strategy.
+	// Defaults to OTEL_TRACES_SAMPLER, or SamplerParentBasedAlwaysOn.
+	Sampler Sampler
+
+	// Ratio is the fraction of traces recorded by the ratio samplers, between
+	// 0 and 1. As in SamplingRule, 0 records nothing. Nil defaults to
+	// OTEL_TRACES_SAMPLER_ARG, or 1. Set it with SampleRatio.
+	Ratio *float64
+
+	// Rules override the base strategy for matching spans. Rules are evaluated
+	// in order and the first match wins. With a parent-based sampler, rules
+	// only apply to root spans and spans with a remote parent are left to the
+	// parent's decision.
+	Rules []SamplingRule
+}
+
+// SamplingRule samples spans that match a Gin route, HTTP method and
+// attributes at a fixed ratio. Empty fields match anything.
+//
+// Example:
+//
+//	Rules: []gintelemetry.SamplingRule{
+//	    {Method: "GET", Route: "/health", Ratio: 0.01},
+//	    {Method: "POST", Route: "/checkout", Ratio: 1},
+//	}
+type SamplingRule struct {
+	// Route is the Gin route template, e.g. "/users/:id".
+	Route string
+
+	// Method is the HTTP request method, e.g. "GET".
+	Method string
+
+	// Attributes must all be present on the span with the given values.
+	Attributes map[string]string
+
+	// Ratio is the fraction of matching traces to record, between 0 and 1.
+	Ratio float64
+}
+
+// SampleRatio returns a pointer to ratio for SamplingConfig.Ratio.
+//
+// Example:
+//
+//	Sampling: gintelemetry.SamplingConfig{
+//	    Sampler: gintelemetry.SamplerParentBasedTraceIDRatio,
+//	    Ratio:   gintelemetry.SampleRatio(0.1),
+//	}
+func SampleRatio(ratio float64) *float64 {
+	return &ratio
+}
+
+func (c *SamplingConfig) validate() error {
+	// Check OTEL_TRACES_SAMPLER if Sampler not set
+	if c.Sampler == "" {
+		c.Sampler = Sampler(strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_SAMPLER"))))
+	}
+	if c.Sampler == "" {
+		c.Sampler = SamplerParentBasedAlwaysOn
+	}
+
+	switch c.Sampler {
+	case SamplerAlwaysOn, SamplerAlwaysOff, SamplerTraceIDRatio,
+		SamplerParentBasedAlwaysOn, SamplerParentBasedAlwaysOff, SamplerParentBasedTraceIDRatio:
+	default:
+		return fmt.Errorf("gintelemetry: unknown sampler %q", c.Sampler)
+	}
+
+	// Check OTEL_TRACES_SAMPLER_ARG if Ratio not set
+	if c.Ratio == nil {
+		ratio := 1.0
+		if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+			var err error
+			ratio, err = strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return fmt.Errorf("gintelemetry: invalid OTEL_TRACES_SAMPLER_ARG %q: %w", arg, err)
+			}
+		}
+		c.Ratio = &ratio
+	}
+	if *c.Ratio < 0 || *c.Ratio > 1 {
+		return fmt.Errorf("gintelemetry: sampling ratio must be between 0 and 1, got %v", *c.Ratio)
+	}
+
+	for i, rule := range c.Rules {
+		if rule.Ratio < 0 || rule.Ratio > 1 {
+			return fmt.Errorf("gintelemetry: sampling rule %d ratio must be between 0 and 1, got %v", i, rule.Ratio)
+		}
+	}
+
+	return nil
+}
+
+// newSampler builds the SDK sampler described by the config.
+func newSampler(cfg SamplingConfig) sdktrace.Sampler {
+	var root sdktrace.Sampler
+	switch cfg.Sampler {
+	case SamplerAlwaysOff, SamplerParentBasedAlwaysOff:
+		root = sdktrace.NeverSample()
+	case SamplerTraceIDRatio, SamplerParentBasedTraceIDRatio:
+		root = sdktrace.TraceIDRatioBased(*cfg.Ratio)
+	default:
+		root = sdktrace.AlwaysSample()
+	}
+
+	if len(cfg.Rules) > 0 {
+		rs := &ruleSampler{fallback: root}
+		for _, rule := range cfg.Rules {
+			rs.rules = append(rs.rules, compiledRule{
+				rule:    rule,
+				sampler: sdktrace.TraceIDRatioBased(rule.Ratio),
+			})
+		}
+		root = rs
+	}
+
+	switch cfg.Sampler {
+	case SamplerParentBasedAlwaysOn, SamplerParentBasedAlwaysOff, SamplerParentBasedTraceIDRatio:
+		return sdktrace.ParentBased(root)
+	}
+	return root
+}
+
+type compiledRule struct {
+	rule    SamplingRule
+	sampler sdktrace.Sampler
+}
+
+// ruleSampler delegates to the sampler of the first rule that matches the
+// span's start attributes, falling back to the base sampler.
+type ruleSampler struct {
+	rules    []compiledRule
+	fallback sdktrace.Sampler
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, r := range s.rules {
+		if r.rule.matches(p.Attributes) {
+			return r.sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleBased{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+func (r SamplingRule) matches(attrs []attribute.KeyValue) bool {
+	if r.Route != "" && lookupAttr(attrs, semconv.HTTPRouteKey) != r.Route {
+		return false
+	}
+	if r.Method != "" && !strings.EqualFold(lookupAttr(attrs, semconv.HTTPRequestMethodKey), r.Method) {
+		return false
+	}
+	for k, v := range r.Attributes {
+		if lookupAttr(attrs, attribute.Key(k)) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupAttr(attrs []attribute.KeyValue, key attribute.Key) string {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+package gintelemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSamplingConfig_Ratio(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SamplingConfig
+		env     string
+		want    float64
+		wantErr bool
+	}{
+		{name: "default", want: 1},
+		{name: "env", env: "0.25", want: 0.25},
+		{name: "explicit zero beats env", cfg: SamplingConfig{Ratio: SampleRatio(0)}, env: "0.5", want: 0},
+		{name: "explicit ratio beats env", cfg: SamplingConfig{Ratio: SampleRatio(0.1)}, env: "0.5", want: 0.1},
+		{name: "invalid env", env: "half", wantErr: true},
+		{name: "out of range", cfg: SamplingConfig{Ratio: SampleRatio(1.5)}, wantErr: true},
+		{name: "rule out of range", cfg: SamplingConfig{Rules: []SamplingRule{{Ratio: -1}}}, wantErr: true},
+		{name: "unknown sampler", cfg: SamplingConfig{Sampler: "sometimes"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.env)
+			cfg := tt.cfg
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *cfg.Ratio != tt.want {
+				t.Errorf("Ratio = %v, want %v", *cfg.Ratio, tt.want)
+			}
+		})
+	}
+}
+
+// sampled reports whether s samples a span with attrs under parent.
+func sampled(s sdktrace.Sampler, parent context.Context, attrs ...attribute.KeyValue) bool {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6}
+	if sc := trace.SpanContextFromContext(parent); sc.IsValid() {
+		traceID = sc.TraceID()
+	}
+	result := s.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: parent,
+		TraceID:       traceID,
+		Name:          "test",
+		Kind:          trace.SpanKindServer,
+		Attributes:    attrs,
+	})
+	return result.Decision == sdktrace.RecordAndSample
+}
+
+func newTestSampler(t *testing.T, cfg SamplingConfig) sdktrace.Sampler {
+	t.Helper()
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	return newSampler(cfg)
+}
+
+func request(method, route string, attrs ...attribute.KeyValue) []attribute.KeyValue {
+	return append(attrs, semconv.HTTPRequestMethodKey.String(method), semconv.HTTPRoute(route))
+}
+
+func TestSampler_Base(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SamplingConfig
+		want bool
+	}{
+		{name: "default", want: true},
+		{name: "always off", cfg: SamplingConfig{Sampler: SamplerAlwaysOff}},
+		{name: "ratio 1", cfg: SamplingConfig{Sampler: SamplerTraceIDRatio, Ratio: SampleRatio(1)}, want: true},
+		{name: "ratio 0", cfg: SamplingConfig{Sampler: SamplerTraceIDRatio, Ratio: SampleRatio(0)}},
+		{name: "parent-based ratio 0", cfg: SamplingConfig{Sampler: SamplerParentBasedTraceIDRatio, Ratio: SampleRatio(0)}},
+		{name: "ratio sampler without a ratio", cfg: SamplingConfig{Sampler: SamplerTraceIDRatio}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sampled(newTestSampler(t, tt.cfg), context.Background()); got != tt.want {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampler_Rules(t *testing.T) {
+	s := newTestSampler(t, SamplingConfig{
+		Sampler: SamplerTraceIDRatio,
+		Ratio:   SampleRatio(1),
+		Rules: []SamplingRule{
+			{Method: "GET", Route: "/health", Ratio: 0},
+			{Route: "/health", Ratio: 1},
+			{Attributes: map[string]string{"tenant.tier": "free"}, Ratio: 0},
+			{Method: "POST", Route: "/checkout", Ratio: 1},
+			{Route: "/checkout", Ratio: 0},
+		},
+	})
+
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  bool
+	}{
+		{name: "route and method", attrs: request("GET", "/health")},
+		{name: "method is case-insensitive", attrs: request("get", "/health")},
+		{name: "route only", attrs: request("POST", "/health"), want: true},
+		{name: "attribute", attrs: request("GET", "/orders", attribute.String("tenant.tier", "free"))},
+		{name: "other attribute value falls back", attrs: request("GET", "/orders", attribute.String("tenant.tier", "paid")), want: true},
+		{name: "first match wins", attrs: request("POST", "/checkout"), want: true},
+		{name: "later rule", attrs: request("GET", "/checkout")},
+		{name: "no attributes falls back", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sampled(s, context.Background(), tt.attrs...); got != tt.want {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// With a parent-based sampler, rules only decide root spans; a remote
+// parent's decision is followed.
+func TestSampler_ParentBasedBypassesRules(t *testing.T) {
+	rules := []SamplingRule{
+		{Route: "/health", Ratio: 0},
+		{Route: "/checkout", Ratio: 1},
+	}
+	parent := func(flags trace.TraceFlags) context.Context {
+		return trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{1},
+			TraceFlags: flags,
+			Remote:     true,
+		}))
+	}
+
+	tests := []struct {
+		name    string
+		sampler Sampler
+		parent  context.Context
+		route   string
+		want    bool
+	}{
+		{name: "root", sampler: SamplerParentBasedAlwaysOn, parent: context.Background(), route: "/health"},
+		{name: "sampled parent", sampler: SamplerParentBasedAlwaysOn, parent: parent(trace.FlagsSampled), route: "/health", want: true},
+		{name: "unsampled parent", sampler: SamplerParentBasedAlwaysOn, parent: parent(0), route: "/checkout"},
+		{name: "not parent-based", sampler: SamplerAlwaysOn, parent: parent(trace.FlagsSampled), route: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSampler(t, SamplingConfig{Sampler: tt.sampler, Rules: rules})
+			if got := sampled(s, tt.parent, request("GET", tt.route)...); got != tt.want {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
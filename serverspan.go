@@ -0,0 +1,80 @@
+package gintelemetry
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serverRequestKey is the request context key of the *serverRequest the
+// middleware shares with the server span otelgin starts.
+type serverRequestKey struct{}
+
+// serverRequest is the state of one request handled by the middleware.
+type serverRequest struct {
+	c       *gin.Context
+	headers *headerCapture
+
+	// spanContext is the server span's context, kept for the exemplars of
+	// the server metrics once otelgin has restored the request context.
+	spanContext trace.SpanContext
+}
+
+// serverRequests wraps next, the metrics and tracing middleware, with the
+// request state that headers are recorded from.
+func serverRequests(headers *headerCapture, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		req := &serverRequest{c: c, headers: headers}
+		c.Request = c.Request.WithContext(context.WithValue(ctx, serverRequestKey{}, req))
+		next(c)
+		c.Request = c.Request.WithContext(ctx)
+	}
+}
+
+// serverSpanContext returns the context of the server span started for the
+// request in ctx, once it has started.
+func serverSpanContext(ctx context.Context) trace.SpanContext {
+	if req, ok := ctx.Value(serverRequestKey{}).(*serverRequest); ok {
+		return req.spanContext
+	}
+	return trace.SpanContext{}
+}
+
+// serverTracerProvider is the tracer provider given to otelgin. The server
+// span it starts for a request handled by the middleware records the
+// captured headers when it ends, after the handlers have run and while it is
+// still recording.
+type serverTracerProvider struct {
+	trace.TracerProvider
+}
+
+func (p serverTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return serverTracer{p.TracerProvider.Tracer(name, opts...)}
+}
+
+type serverTracer struct {
+	trace.Tracer
+}
+
+func (t serverTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := t.Tracer.Start(ctx, name, opts...)
+	req, ok := ctx.Value(serverRequestKey{}).(*serverRequest)
+	if !ok || req.spanContext.IsValid() {
+		return ctx, span
+	}
+	req.spanContext = span.SpanContext()
+	return ctx, serverSpan{Span: span, req: req}
+}
+
+// serverSpan records the request's headers before it ends.
+type serverSpan struct {
+	trace.Span
+	req *serverRequest
+}
+
+func (s serverSpan) End(opts ...trace.SpanEndOption) {
+	s.req.headers.record(s.req.c, s.Span)
+	s.Span.End(opts...)
+}
@@ -0,0 +1,297 @@
+package gintelemetry
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source tells where an effective configuration value came from.
+// Precedence is Config field, then environment variable, then default.
+type Source string
+
+const (
+	// SourceConfig means the value was set in Config.
+	SourceConfig Source = "config"
+
+	// SourceEnv means the value came from an environment variable.
+	SourceEnv Source = "env"
+
+	// SourceDefault means the built-in default was used or, for per-signal
+	// settings, the value was inherited from the top-level setting.
+	SourceDefault Source = "default"
+)
+
+// Setting is one effective configuration value, named after the OTEL_*
+// environment variable that can also set it. Header values are not
+// included, only their names.
+type Setting struct {
+	Name   string
+	Value  string
+	Source Source
+}
+
+// settingField describes how to read one reported setting from a Config.
+type settingField struct {
+	name string
+
+	// raw returns the value as set in Config before validation, or "" when unset.
+	raw func(c *Config) string
+
+	// value returns the effective value after validation. Defaults to raw.
+	value func(c *Config) string
+
+	// env lists the variables that can supply the value. Defaults to name.
+	env []string
+
+	// fromEnv reports whether the variable key supplies a value. Defaults
+	// to a non-empty check.
+	fromEnv func(key string) bool
+}
+
+// settings reports the effective value and source of every setting, given
+// the Config as passed by the caller (base) and after validation (c).
+func (c *Config) settings(base *Config) []Setting {
+	fields := settingFields()
+	out := make([]Setting, 0, len(fields))
+	for _, f := range fields {
+		value := f.raw
+		if f.value != nil {
+			value = f.value
+		}
+		env := f.env
+		if env == nil {
+			env = []string{f.name}
+		}
+
+		fromEnv := f.fromEnv
+		if fromEnv == nil {
+			fromEnv = func(key string) bool { return os.Getenv(key) != "" }
+		}
+
+		source := SourceDefault
+		if f.raw(base) != "" {
+			source = SourceConfig
+		} else {
+			for _, key := range env {
+				if fromEnv(key) {
+					source = SourceEnv
+					break
+				}
+			}
+		}
+		out = append(out, Setting{Name: f.name, Value: value(c), Source: source})
+	}
+	return out
+}
+
+func settingFields() []settingField {
+	fields := []settingField{
+		{
+			name:  "OTEL_SDK_DISABLED",
+			raw:   func(c *Config) string { return boolSetting(c.Disabled) },
+			value: func(c *Config) string { return strconv.FormatBool(c.Disabled) },
+		},
+		{
+			name: "OTEL_SERVICE_NAME",
+			raw:  func(c *Config) string { return c.ServiceName },
+			env:  []string{"OTEL_SERVICE_NAME", "OTEL_RESOURCE_ATTRIBUTES"},
+		},
+		{
+			name: "OTEL_RESOURCE_ATTRIBUTES",
+			raw:  func(c *Config) string { return mapSetting(c.GlobalAttributes, true) },
+		},
+		{
+			name: "OTEL_LOG_LEVEL",
+			raw: func(c *Config) string {
+				if !c.logLevelSet() {
+					return ""
+				}
+				return c.LogLevel.String()
+			},
+			value: func(c *Config) string { return c.getLogLevel().String() },
+		},
+		{name: "OTEL_EXPORTER_OTLP_ENDPOINT", raw: func(c *Config) string { return c.Endpoint }},
+		{name: "OTEL_EXPORTER_OTLP_PROTOCOL", raw: func(c *Config) string { return string(c.Protocol) }},
+		{name: "OTEL_EXPORTER_OTLP_HEADERS", raw: func(c *Config) string { return mapSetting(c.Headers, false) }},
+		{name: "OTEL_EXPORTER_OTLP_COMPRESSION", raw: func(c *Config) string { return string(c.Compression) }},
+		{name: "OTEL_EXPORTER_OTLP_TIMEOUT", raw: func(c *Config) string { return durationSetting(c.Timeout) }, fromEnv: envMillisSet},
+		{name: "OTEL_EXPORTER_OTLP_CERTIFICATE", raw: func(c *Config) string { return c.TLS.CAFile }},
+		{name: "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", raw: func(c *Config) string { return c.TLS.CertFile }},
+		{name: "OTEL_EXPORTER_OTLP_CLIENT_KEY", raw: func(c *Config) string { return c.TLS.KeyFile }},
+	}
+
+	signals := []struct {
+		name string
+		get  func(c *Config) SignalConfig
+	}{
+		{"TRACES", func(c *Config) SignalConfig { return c.Traces }},
+		{"METRICS", func(c *Config) SignalConfig { return c.Metrics }},
+		{"LOGS", func(c *Config) SignalConfig { return c.Logs }},
+	}
+	for _, sg := range signals {
+		get, name := sg.get, strings.ToLower(sg.name)
+		fields = append(fields,
+			settingField{
+				name:  "OTEL_" + sg.name + "_EXPORTER",
+				raw:   func(c *Config) string { return string(get(c).Exporter) },
+				value: func(c *Config) string { return string(c.signal(name, get(c)).exporter) },
+			},
+			settingField{
+				name:  "OTEL_EXPORTER_OTLP_" + sg.name + "_ENDPOINT",
+				raw:   func(c *Config) string { return get(c).Endpoint },
+				value: func(c *Config) string { return c.signal(name, get(c)).endpoint },
+			},
+			settingField{
+				name:  "OTEL_EXPORTER_OTLP_" + sg.name + "_PROTOCOL",
+				raw:   func(c *Config) string { return string(get(c).Protocol) },
+				value: func(c *Config) string { return string(c.signal(name, get(c)).protocol) },
+			},
+			settingField{
+				name:  "OTEL_EXPORTER_OTLP_" + sg.name + "_HEADERS",
+				raw:   func(c *Config) string { return mapSetting(get(c).Headers, false) },
+				value: func(c *Config) string { return mapSetting(c.signal(name, get(c)).headers, false) },
+			},
+			settingField{
+				name:  "OTEL_EXPORTER_OTLP_" + sg.name + "_COMPRESSION",
+				raw:   func(c *Config) string { return string(get(c).Compression) },
+				value: func(c *Config) string { return string(c.signal(name, get(c)).compression) },
+			},
+			settingField{
+				name:    "OTEL_EXPORTER_OTLP_" + sg.name + "_TIMEOUT",
+				raw:     func(c *Config) string { return durationSetting(get(c).Timeout) },
+				value:   func(c *Config) string { return durationSetting(c.signal(name, get(c)).timeout) },
+				fromEnv: envMillisSet,
+			},
+		)
+	}
+
+	fields = append(fields,
+		settingField{
+			name: "OTEL_PROPAGATORS",
+			raw: func(c *Config) string {
+				names := make([]string, len(c.Propagators))
+				for i, p := range c.Propagators {
+					names[i] = string(p)
+				}
+				return strings.Join(names, ",")
+			},
+		},
+		settingField{name: "OTEL_TRACES_SAMPLER", raw: func(c *Config) string { return string(c.Sampling.Sampler) }},
+		settingField{
+			name: "OTEL_TRACES_SAMPLER_ARG",
+			raw: func(c *Config) string {
+				if c.Sampling.Ratio == nil {
+					return ""
+				}
+				return strconv.FormatFloat(*c.Sampling.Ratio, 'g', -1, 64)
+			},
+		},
+	)
+
+	batches := []struct {
+		prefix string
+		get    func(c *Config) BatchConfig
+	}{
+		{"BSP", func(c *Config) BatchConfig { return c.TraceBatch }},
+		{"BLRP", func(c *Config) BatchConfig { return c.LogBatch }},
+	}
+	for _, b := range batches {
+		get := b.get
+		fields = append(fields,
+			settingField{
+				name:    "OTEL_" + b.prefix + "_SCHEDULE_DELAY",
+				raw:     func(c *Config) string { return durationSetting(get(c).ScheduleDelay) },
+				fromEnv: envMillisSet,
+			},
+			settingField{
+				name:    "OTEL_" + b.prefix + "_EXPORT_TIMEOUT",
+				raw:     func(c *Config) string { return durationSetting(get(c).ExportTimeout) },
+				fromEnv: envMillisSet,
+			},
+			settingField{
+				name:    "OTEL_" + b.prefix + "_MAX_QUEUE_SIZE",
+				raw:     func(c *Config) string { return intSetting(get(c).MaxQueueSize) },
+				fromEnv: envIntSet,
+			},
+			settingField{
+				name:    "OTEL_" + b.prefix + "_MAX_EXPORT_BATCH_SIZE",
+				raw:     func(c *Config) string { return intSetting(get(c).MaxExportBatchSize) },
+				fromEnv: envIntSet,
+			},
+		)
+	}
+
+	return append(fields,
+		settingField{
+			name: "OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_REQUEST",
+			raw:  func(c *Config) string { return strings.Join(c.CaptureHeaders.Request, ",") },
+		},
+		settingField{
+			name: "OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_RESPONSE",
+			raw:  func(c *Config) string { return strings.Join(c.CaptureHeaders.Response, ",") },
+		},
+		settingField{name: "OTEL_METRIC_EXPORT_INTERVAL", raw: func(c *Config) string { return durationSetting(c.MetricInterval) }, fromEnv: envMillisSet},
+		settingField{name: "OTEL_METRIC_EXPORT_TIMEOUT", raw: func(c *Config) string { return durationSetting(c.MetricTimeout) }, fromEnv: envMillisSet},
+		settingField{
+			name: "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE",
+			raw:  func(c *Config) string { return string(c.MetricTemporality) },
+		},
+		settingField{name: "OTEL_ATTRIBUTE_COUNT_LIMIT", raw: func(c *Config) string { return intSetting(c.Limits.AttributeCount) }, fromEnv: envIntSet},
+		settingField{name: "OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT", raw: func(c *Config) string { return intSetting(c.Limits.AttributeValueLength) }, fromEnv: envIntSet},
+		settingField{name: "OTEL_SPAN_EVENT_COUNT_LIMIT", raw: func(c *Config) string { return intSetting(c.Limits.SpanEvents) }, fromEnv: envIntSet},
+		settingField{name: "OTEL_SPAN_LINK_COUNT_LIMIT", raw: func(c *Config) string { return intSetting(c.Limits.SpanLinks) }, fromEnv: envIntSet},
+	)
+}
+
+func boolSetting(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
+
+func intSetting(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func durationSetting(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// envIntSet reports whether key holds a value that validation uses. Like a
+// zero Config field, zero falls back to the default.
+func envIntSet(key string) bool {
+	n, err := envInt(key)
+	return err == nil && n != 0
+}
+
+// envMillisSet is envIntSet for durations in milliseconds.
+func envMillisSet(key string) bool {
+	d, err := envMillis(key)
+	return err == nil && d != 0
+}
+
+// mapSetting formats m as sorted "k=v" pairs, or only the keys when
+// withValues is false so that secrets in headers are not reported.
+func mapSetting(m map[string]string, withValues bool) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if withValues {
+		for i, k := range keys {
+			keys[i] = k + "=" + m[k]
+		}
+	}
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,112 @@
+package gintelemetry
+
+import (
+	"testing"
+	"time"
+)
+
+// setting returns the named setting reported for cfg after validation.
+func setting(t *testing.T, cfg Config, name string) Setting {
+	t.Helper()
+	base := cfg
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range cfg.settings(&base) {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("setting %s not reported", name)
+	return Setting{}
+}
+
+func TestSettings_Source(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		setting    string
+		env        string
+		wantValue  string
+		wantSource Source
+	}{
+		{
+			name:       "log level default",
+			setting:    "OTEL_LOG_LEVEL",
+			wantValue:  "INFO",
+			wantSource: SourceDefault,
+		},
+		{
+			name:       "log level from env",
+			setting:    "OTEL_LOG_LEVEL",
+			env:        "warn",
+			wantValue:  "WARN",
+			wantSource: SourceEnv,
+		},
+		{
+			name:       "explicit info level over env",
+			cfg:        Config{LogLevel: LevelInfo, LogLevelSet: true},
+			setting:    "OTEL_LOG_LEVEL",
+			env:        "warn",
+			wantValue:  "INFO",
+			wantSource: SourceConfig,
+		},
+		{
+			name:       "span event limit from env",
+			setting:    "OTEL_SPAN_EVENT_COUNT_LIMIT",
+			env:        "64",
+			wantValue:  "64",
+			wantSource: SourceEnv,
+		},
+		{
+			name:       "zero span event limit in env falls back to default",
+			setting:    "OTEL_SPAN_EVENT_COUNT_LIMIT",
+			env:        "0",
+			wantValue:  "128",
+			wantSource: SourceDefault,
+		},
+		{
+			name:       "span event limit in config over env",
+			cfg:        Config{Limits: LimitsConfig{SpanEvents: 32}},
+			setting:    "OTEL_SPAN_EVENT_COUNT_LIMIT",
+			env:        "64",
+			wantValue:  "32",
+			wantSource: SourceConfig,
+		},
+		{
+			name:       "zero queue size in env falls back to default",
+			setting:    "OTEL_BSP_MAX_QUEUE_SIZE",
+			env:        "0",
+			wantValue:  "2048",
+			wantSource: SourceDefault,
+		},
+		{
+			name:       "zero metric interval in env falls back to default",
+			setting:    "OTEL_METRIC_EXPORT_INTERVAL",
+			env:        "0",
+			wantValue:  time.Minute.String(),
+			wantSource: SourceDefault,
+		},
+		{
+			name:       "metric interval from env",
+			setting:    "OTEL_METRIC_EXPORT_INTERVAL",
+			env:        "5000",
+			wantValue:  (5 * time.Second).String(),
+			wantSource: SourceEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv(tt.setting, tt.env)
+			}
+			tt.cfg.ServiceName = "test"
+			tt.cfg.Exporter = ExporterNone
+			got := setting(t, tt.cfg, tt.setting)
+			if got.Value != tt.wantValue || got.Source != tt.wantSource {
+				t.Errorf("%s = %q from %s, want %q from %s", tt.setting, got.Value, got.Source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package gintelemetry
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TailSamplingConfig configures in-process tail-based sampling. Spans are
+// buffered per trace until its local root span ends, then the whole trace is
+// kept if any span failed, was slow, or matched a rule. Remaining traces are
+// kept at Ratio. Spans that end after their trace was dropped are still
+// exported if they failed, were slow, or matched a rule.
+//
+// Tail sampling only sees spans that passed head sampling, so keep the head
+// sampler at its always-on default when enabling it.
+type TailSamplingConfig struct {
+	// Enabled installs the tail sampling processor in front of the exporter.
+	Enabled bool
+
+	// DecisionWait decides traces without a running local root span once no
+	// span of the trace has ended for this long. Traces whose local root is
+	// still running are never decided by it. Defaults to 5 seconds.
+	DecisionWait time.Duration
+
+	// LatencyThreshold keeps traces containing a span at least this long.
+	// Zero disables the latency check.
+	LatencyThreshold time.Duration
+
+	// Rules keep traces containing a span that matches any rule.
+	Rules []TailSamplingRule
+
+	// Ratio is the fraction of remaining traces to keep, between 0 and 1.
+	// Zero keeps only error, slow and rule-matched traces.
+	Ratio float64
+
+	// MaxTraces bounds the number of traces buffered at once. When full, the
+	// least recently active trace is decided early. Defaults to 10000.
+	MaxTraces int
+
+	// MaxSpansPerTrace bounds the spans buffered for one trace. Extra spans
+	// are dropped. Defaults to 1000.
+	MaxSpansPerTrace int
+}
+
+// TailSamplingRule matches a finished span by name and attributes.
+// Empty fields match anything.
+type TailSamplingRule struct {
+	// SpanName is the exact span name, e.g. "POST /checkout".
+	SpanName string
+
+	// Attributes must all be present on the span with the given values.
+	Attributes map[string]string
+}
+
+func (c *TailSamplingConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.DecisionWait <= 0 {
+		c.DecisionWait = 5 * time.Second
+	}
+	if c.MaxTraces <= 0 {
+		c.MaxTraces = 10000
+	}
+	if c.MaxSpansPerTrace <= 0 {
+		c.MaxSpansPerTrace = 1000
+	}
+	if c.Ratio < 0 || c.Ratio > 1 {
+		return fmt.Errorf("gintelemetry: tail sampling ratio must be between 0 and 1, got %v", c.Ratio)
+	}
+	return nil
+}
+
+func (r TailSamplingRule) matches(s sdktrace.ReadOnlySpan) bool {
+	if r.SpanName != "" && s.Name() != r.SpanName {
+		return false
+	}
+	if len(r.Attributes) > 0 {
+		attrs := s.Attributes()
+		for k, v := range r.Attributes {
+			if lookupAttr(attrs, attribute.Key(k)) != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tailTrace holds the buffered spans of one undecided trace.
+type tailTrace struct {
+	id          trace.TraceID
+	elem        *list.Element
+	deadline    time.Time
+	openRoots   int // local root spans started but not yet ended
+	spans       []sdktrace.ReadOnlySpan
+	interesting bool
+	keep        bool
+}
+
+// tailSamplingProcessor buffers ended spans per trace and forwards the spans
+// of kept traces to the next processor, usually the batch exporter pipeline.
+type tailSamplingProcessor struct {
+	cfg   TailSamplingConfig
+	next  sdktrace.SpanProcessor
+	ratio sdktrace.Sampler
+
+	mu      sync.Mutex
+	pending map[trace.TraceID]*tailTrace
+	order   *list.List // of *tailTrace, least recently active first
+
+	// decided remembers recent decisions so late spans follow their trace.
+	decided      map[trace.TraceID]bool
+	decidedOrder []trace.TraceID
+
+	tracesKept     atomic.Int64
+	tracesDropped  atomic.Int64
+	spansSampled   atomic.Int64
+	spansOverLimit atomic.Int64
+	earlyDecisions atomic.Int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newTailSamplingProcessor(cfg TailSamplingConfig, next sdktrace.SpanProcessor) *tailSamplingProcessor {
+	p := &tailSamplingProcessor{
+		cfg:     cfg,
+		next:    next,
+		ratio:   sdktrace.TraceIDRatioBased(cfg.Ratio),
+		pending: make(map[trace.TraceID]*tailTrace),
+		order:   list.New(),
+		decided: make(map[trace.TraceID]bool),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *tailSamplingProcessor) run() {
+	defer close(p.done)
+
+	interval := p.cfg.DecisionWait / 4
+	if interval > time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.decideExpired(time.Now())
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *tailSamplingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if isLocalRoot(s) {
+		id := s.SpanContext().TraceID()
+		var evicted *tailTrace
+		p.mu.Lock()
+		if _, ok := p.decided[id]; !ok {
+			var t *tailTrace
+			t, evicted = p.touchLocked(id, time.Now())
+			t.openRoots++
+		}
+		p.mu.Unlock()
+		p.decide(evicted)
+	}
+	p.next.OnStart(parent, s)
+}
+
+func (p *tailSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	id := s.SpanContext().TraceID()
+	interesting := p.isInteresting(s)
+
+	p.mu.Lock()
+	if keep, ok := p.decided[id]; ok {
+		// A late span that would have kept the trace is exported on its
+		// own, and the rest of the trace follows it.
+		if !keep && interesting {
+			keep = true
+			p.decided[id] = true
+		}
+		p.mu.Unlock()
+		if keep {
+			p.next.OnEnd(s)
+		} else {
+			p.spansSampled.Add(1)
+		}
+		return
+	}
+
+	t, evicted := p.touchLocked(id, time.Now())
+	if len(t.spans) < p.cfg.MaxSpansPerTrace {
+		t.spans = append(t.spans, s)
+	} else {
+		p.spansOverLimit.Add(1)
+	}
+	t.interesting = t.interesting || interesting
+
+	// The trace is complete in this process once its local roots have ended.
+	var complete *tailTrace
+	if isLocalRoot(s) {
+		if t.openRoots--; t.openRoots <= 0 {
+			p.removeLocked(t)
+			complete = t
+		}
+	}
+	p.mu.Unlock()
+
+	p.decide(evicted, complete)
+}
+
+// isLocalRoot reports whether s is the first span of its trace in this
+// process.
+func isLocalRoot(s sdktrace.ReadOnlySpan) bool {
+	parent := s.Parent()
+	return !parent.IsValid() || parent.IsRemote()
+}
+
+// touchLocked returns the pending trace for id, creating it if needed, and
+// moves its deadline forward. When the buffer is full, the least recently
+// active trace is removed and returned for an early decision. p.mu must be
+// held.
+func (p *tailSamplingProcessor) touchLocked(id trace.TraceID, now time.Time) (*tailTrace, *tailTrace) {
+	var evicted *tailTrace
+	t, ok := p.pending[id]
+	if ok {
+		p.order.MoveToBack(t.elem)
+	} else {
+		if p.order.Len() >= p.cfg.MaxTraces {
+			evicted = p.order.Front().Value.(*tailTrace)
+			p.removeLocked(evicted)
+			p.earlyDecisions.Add(1)
+		}
+		t = &tailTrace{id: id}
+		t.elem = p.order.PushBack(t)
+		p.pending[id] = t
+	}
+	t.deadline = now.Add(p.cfg.DecisionWait)
+	return t, evicted
+}
+
+func (p *tailSamplingProcessor) isInteresting(s sdktrace.ReadOnlySpan) bool {
+	if s.Status().Code == codes.Error {
+		return true
+	}
+	if p.cfg.LatencyThreshold > 0 && s.EndTime().Sub(s.StartTime()) >= p.cfg.LatencyThreshold {
+		return true
+	}
+	for _, rule := range p.cfg.Rules {
+		if rule.matches(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeLocked removes t from the pending traces and records its decision
+// so spans arriving later follow it. p.mu must be held.
+func (p *tailSamplingProcessor) removeLocked(t *tailTrace) {
+	p.order.Remove(t.elem)
+	delete(p.pending, t.id)
+
+	t.keep = t.interesting || p.ratio.ShouldSample(sdktrace.SamplingParameters{
+		TraceID: t.id,
+	}).Decision == sdktrace.RecordAndSample
+	p.rememberLocked(t.id, t.keep)
+}
+
+// decideExpired decides the traces without a running local root that have
+// been idle for DecisionWait.
+func (p *tailSamplingProcessor) decideExpired(now time.Time) {
+	var expired []*tailTrace
+	p.mu.Lock()
+	for e := p.order.Front(); e != nil; {
+		t := e.Value.(*tailTrace)
+		if t.deadline.After(now) {
+			// The list is in deadline order, so the rest are not due.
+			break
+		}
+		e = e.Next()
+		if t.openRoots > 0 {
+			continue
+		}
+		p.removeLocked(t)
+		expired = append(expired, t)
+	}
+	p.mu.Unlock()
+
+	p.decide(expired...)
+}
+
+func (p *tailSamplingProcessor) decideAll() {
+	var all []*tailTrace
+	p.mu.Lock()
+	for p.order.Len() > 0 {
+		t := p.order.Front().Value.(*tailTrace)
+		p.removeLocked(t)
+		all = append(all, t)
+	}
+	p.mu.Unlock()
+
+	p.decide(all...)
+}
+
+// decide forwards the spans of kept traces and counts the dropped ones.
+// Nil traces are ignored.
+func (p *tailSamplingProcessor) decide(traces ...*tailTrace) {
+	for _, t := range traces {
+		if t == nil {
+			continue
+		}
+		if !t.keep {
+			p.tracesDropped.Add(1)
+			p.spansSampled.Add(int64(len(t.spans)))
+			continue
+		}
+		p.tracesKept.Add(1)
+		for _, s := range t.spans {
+			p.next.OnEnd(s)
+		}
+	}
+}
+
+// rememberLocked records a decision, bounding the history to MaxTraces.
+// p.mu must be held.
+func (p *tailSamplingProcessor) rememberLocked(id trace.TraceID, keep bool) {
+	if len(p.decidedOrder) >= p.cfg.MaxTraces {
+		delete(p.decided, p.decidedOrder[0])
+		p.decidedOrder = p.decidedOrder[1:]
+	}
+	p.decided[id] = keep
+	p.decidedOrder = append(p.decidedOrder, id)
+}
+
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	p.decideAll()
+	return p.next.ForceFlush(ctx)
+}
+
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	p.decideAll()
+	return p.next.Shutdown(ctx)
+}
+
+// registerMetrics reports decision and drop counters on meter.
+func (p *tailSamplingProcessor) registerMetrics(meter metric.Meter) error {
+	traces, err := meter.Int64ObservableCounter("gintelemetry.tail_sampling.traces",
+		metric.WithDescription("Traces decided by the tail sampler"))
+	if err != nil {
+		return err
+	}
+	dropped, err := meter.Int64ObservableCounter("gintelemetry.tail_sampling.spans.dropped",
+		metric.WithDescription("Spans dropped by the tail sampler"))
+	if err != nil {
+		return err
+	}
+	early, err := meter.Int64ObservableCounter("gintelemetry.tail_sampling.early_decisions",
+		metric.WithDescription("Traces decided before DecisionWait because the buffer was full"))
+	if err != nil {
+		return err
+	}
+
+	kept := metric.WithAttributes(attribute.String("decision", "kept"))
+	notKept := metric.WithAttributes(attribute.String("decision", "dropped"))
+	sampled := metric.WithAttributes(attribute.String("reason", "sampled"))
+	overLimit := metric.WithAttributes(attribute.String("reason", "trace_too_large"))
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(traces, p.tracesKept.Load(), kept)
+		o.ObserveInt64(traces, p.tracesDropped.Load(), notKept)
+		o.ObserveInt64(dropped, p.spansSampled.Load(), sampled)
+		o.ObserveInt64(dropped, p.spansOverLimit.Load(), overLimit)
+		o.ObserveInt64(early, p.earlyDecisions.Load())
+		return nil
+	}, traces, dropped, early)
+	return err
+}
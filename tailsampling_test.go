@@ -0,0 +1,226 @@
+package gintelemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTailSamplingTest returns a tracer whose spans pass through a tail
+// sampler into the returned recorder.
+func newTailSamplingTest(t *testing.T, cfg TailSamplingConfig) (trace.Tracer, *tailSamplingProcessor, *tracetest.SpanRecorder) {
+	t.Helper()
+	cfg.Enabled = true
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	p := newTailSamplingProcessor(cfg, recorder)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(p))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tp.Tracer("test"), p, recorder
+}
+
+func endedNames(r *tracetest.SpanRecorder) []string {
+	var names []string
+	for _, s := range r.Ended() {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func TestTailSampling_DecidesWhenRootEnds(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      TailSamplingConfig
+		finish   func(root, child trace.Span)
+		wantKept bool
+	}{
+		{
+			name:   "plain trace is dropped",
+			finish: func(root, child trace.Span) { child.End(); root.End() },
+		},
+		{
+			name: "error on child keeps trace",
+			finish: func(root, child trace.Span) {
+				child.SetStatus(codes.Error, "boom")
+				child.End()
+				root.End()
+			},
+			wantKept: true,
+		},
+		{
+			name: "rule match keeps trace",
+			cfg: TailSamplingConfig{Rules: []TailSamplingRule{
+				{Attributes: map[string]string{"tenant.tier": "enterprise"}},
+			}},
+			finish: func(root, child trace.Span) {
+				child.End()
+				root.SetAttributes(attribute.String("tenant.tier", "enterprise"))
+				root.End()
+			},
+			wantKept: true,
+		},
+		{
+			name: "ratio one keeps trace",
+			cfg:  TailSamplingConfig{Ratio: 1},
+			finish: func(root, child trace.Span) {
+				child.End()
+				root.End()
+			},
+			wantKept: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, _, recorder := newTailSamplingTest(t, tt.cfg)
+			ctx, root := tracer.Start(context.Background(), "root")
+			_, child := tracer.Start(ctx, "child")
+			tt.finish(root, child)
+
+			got := len(recorder.Ended())
+			if tt.wantKept && got != 2 {
+				t.Errorf("exported spans = %v, want root and child", endedNames(recorder))
+			}
+			if !tt.wantKept && got != 0 {
+				t.Errorf("exported spans = %v, want none", endedNames(recorder))
+			}
+		})
+	}
+}
+
+// A request that outlives DecisionWait must still be decided by its root.
+func TestTailSampling_LongRequestFailingAfterDecisionWait(t *testing.T) {
+	tracer, p, recorder := newTailSamplingTest(t, TailSamplingConfig{DecisionWait: 10 * time.Millisecond})
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+
+	p.decideExpired(time.Now().Add(time.Second))
+	if got := endedNames(recorder); len(got) != 0 {
+		t.Fatalf("exported spans before the root ended = %v", got)
+	}
+
+	root.SetStatus(codes.Error, "timeout")
+	root.End()
+	if got := endedNames(recorder); len(got) != 2 {
+		t.Errorf("exported spans = %v, want root and child", got)
+	}
+}
+
+func TestTailSampling_SlowRootKeepsTrace(t *testing.T) {
+	tracer, _, recorder := newTailSamplingTest(t, TailSamplingConfig{LatencyThreshold: time.Second})
+
+	start := time.Now()
+	ctx, root := tracer.Start(context.Background(), "root", trace.WithTimestamp(start))
+	_, child := tracer.Start(ctx, "child", trace.WithTimestamp(start))
+	child.End(trace.WithTimestamp(start.Add(time.Millisecond)))
+	root.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+	if got := endedNames(recorder); len(got) != 2 {
+		t.Errorf("exported spans = %v, want root and child", got)
+	}
+}
+
+func TestTailSampling_LateInterestingSpanIsExported(t *testing.T) {
+	tracer, _, recorder := newTailSamplingTest(t, TailSamplingConfig{})
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, late := tracer.Start(ctx, "late")
+	_, plain := tracer.Start(ctx, "plain")
+	root.End()
+	plain.End()
+	if got := endedNames(recorder); len(got) != 0 {
+		t.Fatalf("exported spans of a dropped trace = %v", got)
+	}
+
+	late.SetStatus(codes.Error, "boom")
+	late.End()
+	got := endedNames(recorder)
+	if len(got) != 1 || got[0] != "late" {
+		t.Errorf("exported spans = %v, want [late]", got)
+	}
+}
+
+func TestTailSampling_RemoteParentIsLocalRoot(t *testing.T) {
+	tracer, _, recorder := newTailSamplingTest(t, TailSamplingConfig{Ratio: 1})
+
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), remote)
+	_, server := tracer.Start(ctx, "server")
+	server.End()
+
+	if got := endedNames(recorder); len(got) != 1 {
+		t.Errorf("exported spans = %v, want [server]", got)
+	}
+}
+
+func TestTailSampling_IdleTraceWithoutRootExpires(t *testing.T) {
+	tracer, p, recorder := newTailSamplingTest(t, TailSamplingConfig{DecisionWait: time.Minute, Ratio: 1})
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+
+	// Forget the root, as if it started before the trace was evicted.
+	p.mu.Lock()
+	p.pending[root.SpanContext().TraceID()].openRoots = 0
+	p.mu.Unlock()
+	child.End()
+
+	p.decideExpired(time.Now())
+	if got := endedNames(recorder); len(got) != 0 {
+		t.Fatalf("exported spans before DecisionWait = %v", got)
+	}
+	p.decideExpired(time.Now().Add(2 * time.Minute))
+	if got := endedNames(recorder); len(got) != 1 {
+		t.Errorf("exported spans after DecisionWait = %v, want [child]", got)
+	}
+}
+
+func TestTailSampling_Limits(t *testing.T) {
+	tracer, p, recorder := newTailSamplingTest(t, TailSamplingConfig{
+		Ratio:            1,
+		MaxTraces:        2,
+		MaxSpansPerTrace: 2,
+	})
+
+	var roots []trace.Span
+	for range 3 {
+		_, root := tracer.Start(context.Background(), "root")
+		roots = append(roots, root)
+	}
+	if got := p.earlyDecisions.Load(); got != 1 {
+		t.Errorf("early decisions = %d, want 1", got)
+	}
+
+	ctx := trace.ContextWithSpan(context.Background(), roots[2])
+	for range 3 {
+		_, child := tracer.Start(ctx, "child")
+		child.End()
+	}
+	if got := p.spansOverLimit.Load(); got != 1 {
+		t.Errorf("spans over limit = %d, want 1", got)
+	}
+
+	for _, root := range roots {
+		root.End()
+	}
+	// The evicted first trace was kept, so its root follows the decision.
+	// The third trace keeps only its first two spans.
+	if got := len(recorder.Ended()); got != 4 {
+		t.Errorf("exported spans = %v, want 4", endedNames(recorder))
+	}
+}
@@ -0,0 +1,245 @@
+package gintelemetry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TLSConfig configures TLS and mutual TLS for the OTLP exporters.
+// Certificates loaded from files are reloaded when the files change, so
+// certificate rotation does not require a restart.
+type TLSConfig struct {
+	// CAFile is a PEM file with the CA certificates used to verify the
+	// collector. Defaults to OTEL_EXPORTER_OTLP_CERTIFICATE.
+	CAFile string
+
+	// CAPEM holds PEM-encoded CA certificates, added to those from CAFile.
+	CAPEM []byte
+
+	// CertFile and KeyFile are the PEM client certificate and key for mutual TLS.
+	// Default to OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE and OTEL_EXPORTER_OTLP_CLIENT_KEY.
+	CertFile string
+	KeyFile  string
+
+	// ServerName overrides the name used to verify the collector certificate.
+	ServerName string
+
+	// MinVersion is the minimum TLS version, e.g. tls.VersionTLS13.
+	// Defaults to tls.VersionTLS12.
+	MinVersion uint16
+
+	// ReloadInterval is how often certificate files are checked for changes.
+	// Defaults to 30 seconds.
+	ReloadInterval time.Duration
+}
+
+func (c *TLSConfig) isSet() bool {
+	return c.CAFile != "" || len(c.CAPEM) > 0 || c.CertFile != "" || c.KeyFile != "" ||
+		c.ServerName != "" || c.MinVersion != 0
+}
+
+func (c *TLSConfig) validate() error {
+	// Check the standard OTLP certificate variables if files not set
+	if c.CAFile == "" {
+		c.CAFile = os.Getenv("OTEL_EXPORTER_OTLP_CERTIFICATE")
+	}
+	if c.CertFile == "" {
+		c.CertFile = os.Getenv("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE")
+	}
+	if c.KeyFile == "" {
+		c.KeyFile = os.Getenv("OTEL_EXPORTER_OTLP_CLIENT_KEY")
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("gintelemetry: TLS CertFile and KeyFile must be set together")
+	}
+	if c.MinVersion == 0 {
+		c.MinVersion = tls.VersionTLS12
+	}
+	if c.ReloadInterval <= 0 {
+		c.ReloadInterval = 30 * time.Second
+	}
+	return nil
+}
+
+// newCertReloader loads the configured certificates. It returns nil when no
+// TLS settings are configured, leaving the exporters on their defaults
+// (system roots, no client certificate).
+func newCertReloader(cfg TLSConfig) (*certReloader, error) {
+	if !cfg.isSet() {
+		return nil, nil
+	}
+
+	r := &certReloader{cfg: cfg}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// tlsConfig builds a client tls.Config for a collector at endpoint, a
+// host:port address. It returns nil when r is nil.
+func (r *certReloader) tlsConfig(endpoint string) *tls.Config {
+	if r == nil {
+		return nil
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: r.cfg.MinVersion,
+		ServerName: r.cfg.ServerName,
+	}
+	if r.cfg.CertFile != "" {
+		tlsCfg.GetClientCertificate = r.clientCertificate
+	}
+	if r.roots != nil {
+		// Standard verification would pin the roots loaded at startup. Verify
+		// against the current pool instead so a rotated CA takes effect.
+		host := r.cfg.ServerName
+		if host == "" {
+			host = endpointHost(endpoint)
+		}
+		tlsCfg.InsecureSkipVerify = true
+		tlsCfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			return r.verifyConnection(cs, host)
+		}
+	}
+	return tlsCfg
+}
+
+// endpointHost returns the host of a host:port endpoint, or "" for unix
+// sockets.
+func endpointHost(endpoint string) string {
+	if strings.HasPrefix(endpoint, "unix:") {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return host
+}
+
+// certReloader holds the current CA pool and client certificate and reloads
+// them when the backing files change.
+type certReloader struct {
+	cfg TLSConfig
+
+	mu        sync.Mutex
+	roots     *x509.CertPool
+	cert      *tls.Certificate
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+func (r *certReloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.cfg.CAFile, r.cfg.CertFile, r.cfg.KeyFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("gintelemetry: failed to read TLS file: %w", err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+
+	var roots *x509.CertPool
+	if r.cfg.CAFile != "" || len(r.cfg.CAPEM) > 0 {
+		roots = x509.NewCertPool()
+		if r.cfg.CAFile != "" {
+			pem, err := os.ReadFile(r.cfg.CAFile)
+			if err != nil {
+				return fmt.Errorf("gintelemetry: failed to read CA file: %w", err)
+			}
+			if !roots.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("gintelemetry: no certificates found in CA file %s", r.cfg.CAFile)
+			}
+		}
+		if len(r.cfg.CAPEM) > 0 && !roots.AppendCertsFromPEM(r.cfg.CAPEM) {
+			return fmt.Errorf("gintelemetry: no certificates found in CAPEM")
+		}
+	}
+
+	var cert *tls.Certificate
+	if r.cfg.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("gintelemetry: failed to load client certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	r.mu.Lock()
+	r.roots = roots
+	r.cert = cert
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// maybeReload reloads the files if ReloadInterval has passed and any of them
+// changed. A failed reload keeps the previous certificates.
+func (r *certReloader) maybeReload() {
+	r.mu.Lock()
+	if time.Since(r.lastCheck) < r.cfg.ReloadInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCheck = time.Now()
+	changed := false
+	for path, mod := range r.modTimes {
+		info, err := os.Stat(path)
+		if err == nil && !info.ModTime().Equal(mod) {
+			changed = true
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	if changed {
+		_ = r.load()
+	}
+}
+
+func (r *certReloader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.maybeReload()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cert, nil
+}
+
+// verifyConnection verifies the collector certificate against the current
+// roots and host, which may be a DNS name or an IP address.
+func (r *certReloader) verifyConnection(cs tls.ConnectionState, host string) error {
+	if host == "" {
+		return errors.New("gintelemetry: no host to verify the collector certificate against, set TLS.ServerName")
+	}
+
+	r.maybeReload()
+	r.mu.Lock()
+	roots := r.roots
+	r.mu.Unlock()
+
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("gintelemetry: collector presented no certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       host,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
@@ -0,0 +1,224 @@
+package gintelemetry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCA is a certificate authority for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// serverCert issues a server certificate for the given DNS names and IPs.
+func (ca *testCA) serverCert(t *testing.T, dnsNames []string, ips []net.IP) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writeCAFile writes the CA certificate to a file in a temporary directory.
+func (ca *testCA) writeCAFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, ca.pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startTLSServer serves cert on 127.0.0.1 and returns its host:port.
+func startTLSServer(t *testing.T, cert tls.Certificate) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv.Listener.Addr().String()
+}
+
+func dialWith(tlsCfg *tls.Config, endpoint string) error {
+	conn, err := tls.Dial("tcp", endpoint, tlsCfg)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func TestCertReloader_VerifiesHost(t *testing.T) {
+	ca := newTestCA(t)
+	caFile := ca.writeCAFile(t)
+	loopback := []net.IP{net.ParseIP("127.0.0.1")}
+
+	tests := []struct {
+		name       string
+		dnsNames   []string
+		ips        []net.IP
+		serverName string
+		wantErr    bool
+	}{
+		{
+			name:     "IP endpoint with certificate for another host",
+			dnsNames: []string{"other.test"},
+			wantErr:  true,
+		},
+		{
+			name: "IP endpoint with certificate for the IP",
+			ips:  loopback,
+		},
+		{
+			name:       "ServerName overrides the endpoint host",
+			dnsNames:   []string{"other.test"},
+			serverName: "other.test",
+		},
+		{
+			name:       "ServerName not in certificate",
+			ips:        loopback,
+			serverName: "collector.test",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := startTLSServer(t, ca.serverCert(t, tt.dnsNames, tt.ips))
+			cfg := TLSConfig{CAFile: caFile, ServerName: tt.serverName}
+			if err := cfg.validate(); err != nil {
+				t.Fatal(err)
+			}
+			r, err := newCertReloader(cfg)
+			if err != nil {
+				t.Fatalf("newCertReloader() error = %v", err)
+			}
+			err = dialWith(r.tlsConfig(endpoint), endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("handshake error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCertReloader_NoHost(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := TLSConfig{CAPEM: ca.pem}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := newCertReloader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := startTLSServer(t, ca.serverCert(t, []string{"localhost"}, []net.IP{net.ParseIP("127.0.0.1")}))
+
+	// A unix socket endpoint has no host to verify against.
+	tlsCfg := r.tlsConfig("unix:///var/run/otel.sock")
+	if err := dialWith(tlsCfg, endpoint); err == nil {
+		t.Error("handshake succeeded without a host to verify")
+	}
+}
+
+func TestCertReloader_ReloadsCA(t *testing.T) {
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+	caFile := oldCA.writeCAFile(t)
+	cfg := TLSConfig{CAFile: caFile}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.ReloadInterval = time.Nanosecond
+	r, err := newCertReloader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := startTLSServer(t, newCA.serverCert(t, nil, []net.IP{net.ParseIP("127.0.0.1")}))
+	tlsCfg := r.tlsConfig(endpoint)
+
+	if err := dialWith(tlsCfg, endpoint); err == nil {
+		t.Fatal("handshake succeeded before the CA was rotated")
+	}
+
+	if err := os.WriteFile(caFile, newCA.pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(caFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := dialWith(tlsCfg, endpoint); err != nil {
+		t.Errorf("handshake after CA rotation error = %v", err)
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"collector:4317", "collector"},
+		{"127.0.0.1:4317", "127.0.0.1"},
+		{"[::1]:4317", "::1"},
+		{"collector", "collector"},
+		{"unix:///var/run/otel.sock", ""},
+	}
+	for _, tt := range tests {
+		if got := endpointHost(tt.endpoint); got != tt.want {
+			t.Errorf("endpointHost(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
@@ -5,11 +5,13 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type TraceAPI struct {
-	tracer trace.Tracer
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
 }
 
 type Attribute = attribute.KeyValue
@@ -111,6 +113,31 @@ func (TraceAPI) SetStatus(ctx context.Context, code codes.Code, description stri
 	}
 }
 
+// Inject writes the span context and baggage from ctx into carrier using the
+// configured propagators. Use this for outbound calls so downstream services
+// continue the same trace.
+//
+// Example:
+//
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	tel.Trace().Inject(ctx, propagation.HeaderCarrier(req.Header))
+func (t TraceAPI) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	if t.propagator != nil {
+		t.propagator.Inject(ctx, carrier)
+	}
+}
+
+// Extract reads a remote span context and baggage from carrier using the
+// configured propagators and returns a context that contains them.
+// Use this for consumers that receive work outside of the Gin middleware,
+// such as message queue handlers.
+func (t TraceAPI) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if t.propagator == nil {
+		return ctx
+	}
+	return t.propagator.Extract(ctx, carrier)
+}
+
 // SpanFromContext returns the current span from the context.
 func (TraceAPI) SpanFromContext(ctx context.Context) trace.Span {
 	return trace.SpanFromContext(ctx)
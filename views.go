@@ -0,0 +1,134 @@
+package gintelemetry
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// View changes how matching instruments are aggregated and exported. Views
+// are evaluated in order and the first match wins; instruments that match
+// no view keep the SDK defaults.
+//
+// Example:
+//
+//	Views: []gintelemetry.View{
+//	    {Instrument: "*_duration_ms", Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}},
+//	    {Instrument: "*_bytes", Exponential: true},
+//	    {Instrument: "http.server.*", AttributeKeys: []string{"http.route", "http.request.method"}},
+//	    {Instrument: "debug_*", Drop: true},
+//	}
+type View struct {
+	// Instrument matches instrument names. "*" matches any run of characters
+	// and "?" matches one character.
+	Instrument string
+
+	// Meter restricts the view to instruments from the meter with this
+	// name. Empty matches every meter.
+	Meter string
+
+	// Rename exports the instrument under a new name. Instrument must not
+	// contain wildcards.
+	Rename string
+
+	// Description replaces the instrument description.
+	Description string
+
+	// Drop stops the instrument from being exported.
+	Drop bool
+
+	// Buckets sets explicit histogram bucket boundaries, in increasing order.
+	Buckets []float64
+
+	// Exponential switches histograms to base-2 exponential buckets, which
+	// adapt to the recorded range without choosing boundaries up front.
+	Exponential bool
+
+	// MaxBuckets limits the number of exponential buckets. Defaults to 160.
+	MaxBuckets int
+
+	// AttributeKeys, when set, keeps only these attribute keys and drops
+	// the rest, which reduces the number of exported series.
+	AttributeKeys []string
+}
+
+func (v View) validate(i int) error {
+	prefix := fmt.Sprintf("gintelemetry: Views[%d]", i)
+	switch {
+	case v.Instrument == "":
+		return fmt.Errorf("%s: Instrument is required", prefix)
+	case v.Rename != "" && strings.ContainsAny(v.Instrument, "*?"):
+		return fmt.Errorf("%s: Rename cannot be used with a wildcard Instrument %q", prefix, v.Instrument)
+	case v.Drop && (v.Rename != "" || len(v.Buckets) > 0 || v.Exponential || v.AttributeKeys != nil):
+		return fmt.Errorf("%s: Drop cannot be combined with other settings", prefix)
+	case len(v.Buckets) > 0 && v.Exponential:
+		return fmt.Errorf("%s: Buckets and Exponential are mutually exclusive", prefix)
+	case v.MaxBuckets < 0:
+		return fmt.Errorf("%s: MaxBuckets cannot be negative", prefix)
+	}
+	for j := 1; j < len(v.Buckets); j++ {
+		if v.Buckets[j] <= v.Buckets[j-1] {
+			return fmt.Errorf("%s: Buckets must be in increasing order", prefix)
+		}
+	}
+	return nil
+}
+
+// sdkView converts v to an SDK view. v must have been validated.
+func (v View) sdkView() sdkmetric.View {
+	stream := sdkmetric.Stream{
+		Name:        v.Rename,
+		Description: v.Description,
+	}
+	switch {
+	case v.Drop:
+		stream.Aggregation = sdkmetric.AggregationDrop{}
+	case len(v.Buckets) > 0:
+		stream.Aggregation = sdkmetric.AggregationExplicitBucketHistogram{Boundaries: v.Buckets}
+	case v.Exponential:
+		maxSize := v.MaxBuckets
+		if maxSize == 0 {
+			maxSize = 160
+		}
+		stream.Aggregation = sdkmetric.AggregationBase2ExponentialHistogram{
+			MaxSize:  int32(maxSize),
+			MaxScale: 20,
+		}
+	}
+	if v.AttributeKeys != nil {
+		keys := make([]attribute.Key, len(v.AttributeKeys))
+		for i, k := range v.AttributeKeys {
+			keys[i] = attribute.Key(k)
+		}
+		// Keep the cardinality overflow marker, or the overflow series
+		// would merge into the series without the view's attributes.
+		allow := attribute.NewAllowKeysFilter(keys...)
+		stream.AttributeFilter = func(kv attribute.KeyValue) bool {
+			return kv.Key == overflowKey || allow(kv)
+		}
+	}
+	return sdkmetric.NewView(sdkmetric.Instrument{
+		Name:  v.Instrument,
+		Scope: instrumentation.Scope{Name: v.Meter},
+	}, stream)
+}
+
+// newView combines views into a single SDK view that applies the first
+// matching one, so an instrument is never exported twice.
+func newView(views []View) sdkmetric.View {
+	compiled := make([]sdkmetric.View, len(views))
+	for i, v := range views {
+		compiled[i] = v.sdkView()
+	}
+	return func(inst sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+		for _, view := range compiled {
+			if stream, ok := view(inst); ok {
+				return stream, true
+			}
+		}
+		return sdkmetric.Stream{}, false
+	}
+}